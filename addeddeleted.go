@@ -0,0 +1,40 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteAddedDeleted writes the inserted lines to addW and the deleted lines to delW, for
+// processing them separately like linting only added lines. Each line is written with its line
+// number, starting at 1, and its marker, such as "12:+foo" for line 12 of the new sequence and
+// "7:-bar" for line 7 of the old sequence. Lines are terminated by a newline, also the last line
+// of a sequence without one. Equal lines are not written.
+func WriteAddedDeleted(addW, delW io.Writer, edits []Edit) error {
+	add := bufio.NewWriter(addW)
+	del := bufio.NewWriter(delW)
+	var oldLine, newLine int
+	for _, e := range edits {
+		var err error
+		switch e.Op {
+		case Eq:
+			oldLine++
+			newLine++
+		case Ins:
+			newLine++
+			_, err = fmt.Fprintf(add, "%d:+%s\n", newLine, strings.TrimSuffix(e.NewLine, "\n"))
+		case Del:
+			oldLine++
+			_, err = fmt.Fprintf(del, "%d:-%s\n", oldLine, strings.TrimSuffix(e.OldLine, "\n"))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := add.Flush(); err != nil {
+		return err
+	}
+	return del.Flush()
+}
@@ -0,0 +1,51 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteAddedDeleted(t *testing.T) {
+	tests := map[string]struct {
+		old, new []string
+		wantAdd  string
+		wantDel  string
+	}{
+		"Equal": {
+			old: []string{"a\n"},
+			new: []string{"a\n"},
+		},
+		"Changes": {
+			old:     []string{"a\n", "b\n", "c\n", "d\n"},
+			new:     []string{"a\n", "x\n", "c\n", "y\n", "z"},
+			wantAdd: "2:+x\n4:+y\n5:+z\n",
+			wantDel: "2:-b\n4:-d\n",
+		},
+		"OnlyAdded": {
+			new:     []string{"a\n", "b\n"},
+			wantAdd: "1:+a\n2:+b\n",
+		},
+		"OnlyDeleted": {
+			old:     []string{"a\n", "b"},
+			new:     []string{"a\n"},
+			wantDel: "2:-b\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var add, del strings.Builder
+			if err := diff.WriteAddedDeleted(&add, &del, diff.Lines(test.old, test.new)); err != nil {
+				t.Fatalf("WriteAddedDeleted() error: %v", err)
+			}
+			if got := add.String(); got != test.wantAdd {
+				t.Errorf("WriteAddedDeleted() added =\n%s\nwant:\n%s", got, test.wantAdd)
+			}
+			if got := del.String(); got != test.wantDel {
+				t.Errorf("WriteAddedDeleted() deleted =\n%s\nwant:\n%s", got, test.wantDel)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package diff
+
+import "time"
+
+// AlgoStats are counters describing the work of the Myers algorithm computing an edit script,
+// for understanding why some inputs are slow.
+type AlgoStats struct {
+	D           int // edit distance, the number of deleted and inserted lines
+	Iterations  int // iterations of the search, one for each edit distance d from 0 to D
+	MaxDiagonal int // largest distance |k| from the main diagonal of a diagonal explored
+	Snakes      int // runs of equal lines in the edit script
+	Comparisons int // line comparisons made during the search
+}
+
+// LinesWithStats computes the edits transforming oldLines into newLines like [Lines] and also
+// returns counters describing the work of the algorithm. It counts the comparisons and explored
+// diagonals separately from [Lines], so plain diffs do not pay for the instrumentation.
+func LinesWithStats(oldLines, newLines []string) ([]Edit, AlgoStats) {
+	n, m := len(oldLines), len(newLines)
+	var stats AlgoStats
+	if n+m == 0 {
+		return nil, stats
+	}
+	trace := shortestEdit(n, m, -m, n, time.Time{}, func(x, y int) bool {
+		stats.Comparisons++
+		return oldLines[x] == newLines[y]
+	}, func(k int) {
+		stats.MaxDiagonal = max(stats.MaxDiagonal, k, -k)
+	})
+	stats.Iterations = len(trace)
+	stats.D = len(trace) - 1
+
+	ops := compact(backtrack(n, m, -m, n, trace), oldLines, newLines)
+	for i, op := range ops {
+		if op == Eq && (i == 0 || ops[i-1] != Eq) {
+			stats.Snakes++
+		}
+	}
+	return toEdits(ops, oldLines, newLines), stats
+}
@@ -0,0 +1,49 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestLinesWithStats(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want diff.AlgoStats
+	}{
+		"BothEmpty": {},
+		"Equal": {
+			a:    []string{"a", "b", "c"},
+			b:    []string{"a", "b", "c"},
+			want: diff.AlgoStats{D: 0, Iterations: 1, MaxDiagonal: 0, Snakes: 1, Comparisons: 3},
+		},
+		"AllInserted": {
+			b:    []string{"a", "b"},
+			want: diff.AlgoStats{D: 2, Iterations: 3, MaxDiagonal: 2, Snakes: 0, Comparisons: 0},
+		},
+		"EndReachedBeforeOuterDiagonal": {
+			// the search ends on diagonal n-m = 2 in iteration D = 4 before exploring diagonal 4
+			a:    []string{"a", "b", "c", "d"},
+			b:    []string{"a", "x"},
+			want: diff.AlgoStats{D: 4, Iterations: 5, MaxDiagonal: 3, Snakes: 1, Comparisons: 4},
+		},
+		"OneChange": {
+			a:    []string{"a", "b", "c"},
+			b:    []string{"a", "x", "c"},
+			want: diff.AlgoStats{D: 2, Iterations: 3, MaxDiagonal: 2, Snakes: 2, Comparisons: 5},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			edits, stats := diff.LinesWithStats(test.a, test.b)
+			if want := diff.Lines(test.a, test.b); !slices.Equal(edits, want) {
+				t.Errorf("LinesWithStats() edits = %v, want %v", edits, want)
+			}
+			if stats != test.want {
+				t.Errorf("LinesWithStats() stats = %+v, want %+v", stats, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteGitHubAnnotations writes the edits to w as GitHub Actions error annotations on the file
+// at path, such as
+//
+//	::error file=main.go,line=3::added line: foo
+//
+// so that a CI job turns a diff into inline annotations on a pull request. Each inserted line is
+// annotated at its line number in the new file. A deleted line has no line in the new file, so
+// it is annotated at the new line following the deletion, or at the last line if the deletion is
+// at the end of the new file. Equal lines are not annotated. The path and the line content are
+// escaped as required by workflow commands.
+func WriteGitHubAnnotations(w io.Writer, path string, edits []Edit) error {
+	_, del := countChanges(edits)
+	newLines := len(edits) - del
+	file := escapeAnnotationProperty(path)
+
+	bw := bufio.NewWriter(w)
+	var newLine int
+	for _, e := range edits {
+		var err error
+		switch e.Op {
+		case Eq:
+			newLine++
+		case Ins:
+			newLine++
+			_, err = fmt.Fprintf(bw, "::error file=%s,line=%d::added line: %s\n", file, newLine, escapeAnnotationData(e.NewLine))
+		case Del:
+			line := max(min(newLine+1, newLines), 1)
+			_, err = fmt.Fprintf(bw, "::error file=%s,line=%d::removed line: %s\n", file, line, escapeAnnotationData(e.OldLine))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+var (
+	annotationDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	annotationPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// escapeAnnotationData escapes the message of a workflow command. The line terminator of line is
+// dropped.
+func escapeAnnotationData(line string) string {
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return annotationDataEscaper.Replace(line)
+}
+
+// escapeAnnotationProperty escapes a property value of a workflow command.
+func escapeAnnotationProperty(s string) string {
+	return annotationPropertyEscaper.Replace(s)
+}
@@ -0,0 +1,73 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		path string
+		old  []string
+		new  []string
+		want string
+	}{
+		"Equal": {
+			path: "a.txt",
+			old:  []string{"a\n", "b\n"},
+			new:  []string{"a\n", "b\n"},
+		},
+		"Insertions": {
+			path: "a.txt",
+			old:  []string{"a\n", "c\n"},
+			new:  []string{"a\n", "b\n", "c\n", "d\n"},
+			want: "::error file=a.txt,line=2::added line: b\n" +
+				"::error file=a.txt,line=4::added line: d\n",
+		},
+		"DeletionAnnotatesFollowingLine": {
+			path: "a.txt",
+			old:  []string{"a\n", "b\n", "c\n"},
+			new:  []string{"a\n", "c\n"},
+			want: "::error file=a.txt,line=2::removed line: b\n",
+		},
+		"DeletionAtEndAnnotatesLastLine": {
+			path: "a.txt",
+			old:  []string{"a\n", "b\n", "c\n"},
+			new:  []string{"a\n"},
+			want: "::error file=a.txt,line=1::removed line: b\n" +
+				"::error file=a.txt,line=1::removed line: c\n",
+		},
+		"DeletionOfAllLines": {
+			path: "a.txt",
+			old:  []string{"a\n"},
+			want: "::error file=a.txt,line=1::removed line: a\n",
+		},
+		"Modification": {
+			path: "a.txt",
+			old:  []string{"a\n", "b\n", "c\n"},
+			new:  []string{"a\n", "x\n", "c\n"},
+			want: "::error file=a.txt,line=2::removed line: b\n" +
+				"::error file=a.txt,line=2::added line: x\n",
+		},
+		"Escaping": {
+			path: "dir,1/a:b.txt",
+			old:  []string{"a\n"},
+			new:  []string{"a\n", "100%\r\n"},
+			want: "::error file=dir%2C1/a%3Ab.txt,line=2::added line: 100%25\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := diff.WriteGitHubAnnotations(&sb, test.path, diff.Lines(test.old, test.new)); err != nil {
+				t.Fatalf("WriteGitHubAnnotations() error: %v", err)
+			}
+			if got := sb.String(); got != test.want {
+				t.Errorf("WriteGitHubAnnotations() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
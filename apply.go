@@ -0,0 +1,32 @@
+package diff
+
+import "fmt"
+
+// Apply applies the edits to a and returns the new sequence, so that Apply(a, Lines(a, b)) equals
+// b. Equal lines are taken from the NewLine of their edit, as options of [Lines] can match lines
+// that differ. It returns an error if the edits do not match a: the OldLine of a Del or Eq edit
+// must equal the current line of a and the edits must cover all of a.
+func Apply(a []string, edits []Edit) ([]string, error) {
+	b := make([]string, 0, len(a))
+	var x int
+	for _, e := range edits {
+		if e.Op == Ins {
+			b = append(b, e.NewLine)
+			continue
+		}
+		if x >= len(a) {
+			return nil, fmt.Errorf("diff: edits go past the end of %d lines", len(a))
+		}
+		if e.OldLine != a[x] {
+			return nil, fmt.Errorf("diff: edits do not match line %d", x+1)
+		}
+		if e.Op == Eq {
+			b = append(b, e.NewLine)
+		}
+		x++
+	}
+	if x != len(a) {
+		return nil, fmt.Errorf("diff: edits end at line %d of %d lines", x, len(a))
+	}
+	return b, nil
+}
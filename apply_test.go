@@ -0,0 +1,80 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestApply(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		opts []diff.LinesOption
+	}{
+		"Empty": {},
+		"Equal": {
+			a: []string{"a\n", "b\n"},
+			b: []string{"a\n", "b\n"},
+		},
+		"Changes": {
+			a: []string{"a\n", "b\n", "c\n", "d"},
+			b: []string{"x\n", "b\n", "d\n", "e", ""},
+		},
+		"AllInserted": {
+			b: []string{"a\n", "b\n"},
+		},
+		"AllDeleted": {
+			a: []string{"a\n", "b\n"},
+		},
+		"EqualLinesThatDiffer": {
+			a:    []string{"a  b\n", "c\n"},
+			b:    []string{"a b\n", "d\n"},
+			opts: []diff.LinesOption{diff.WithIgnoreSpaceChange()},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := diff.Apply(test.a, diff.Lines(test.a, test.b, test.opts...))
+			if err != nil {
+				t.Fatalf("Apply() error: %v", err)
+			}
+			if !slices.Equal(got, test.b) {
+				t.Errorf("Apply() = %q, want %q", got, test.b)
+			}
+		})
+	}
+}
+
+func TestApplyMismatch(t *testing.T) {
+	a := []string{"a\n", "b\n"}
+
+	tests := map[string][]diff.Edit{
+		"DelMismatch": {
+			{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+			{Op: diff.Del, OldLine: "c\n"},
+		},
+		"EqMismatch": {
+			{Op: diff.Eq, OldLine: "x\n", NewLine: "x\n"},
+			{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		},
+		"PastEnd": {
+			{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+			{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+			{Op: diff.Del, OldLine: "c\n"},
+		},
+		"NotCovered": {
+			{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+			{Op: diff.Ins, NewLine: "c\n"},
+		},
+	}
+
+	for name, edits := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := diff.Apply(a, edits); err == nil {
+				t.Error("Apply() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package diff
+
+import "time"
+
+// LinesBanded computes the edits transforming oldLines into newLines like [Lines] but only
+// searches the diagonals k = x-y of the edit graph with |k| <= window, widened to reach the
+// diagonal len(oldLines)-len(newLines) where the edit script ends. This trades minimality for
+// speed on large, mostly aligned inputs with local changes: the work is bounded by the band
+// instead of the edit distance squared. The edits are not minimal if the shortest edit script
+// strays further than window lines from the main diagonal, for example by moving a block of
+// lines across a larger distance. A window of at least len(oldLines)+len(newLines) is
+// equivalent to [Lines]. It panics if window is less than 1.
+func LinesBanded(oldLines, newLines []string, window int) []Edit {
+	if window < 1 {
+		panic("diff: window less than 1")
+	}
+	n, m := len(oldLines), len(newLines)
+	if n+m == 0 {
+		return nil
+	}
+	lo := max(min(-window, n-m), -m)
+	hi := min(max(window, n-m), n)
+	trace := shortestEdit(n, m, lo, hi, time.Time{}, func(x, y int) bool {
+		return oldLines[x] == newLines[y]
+	}, nil)
+	ops := compact(backtrack(n, m, lo, hi, trace), oldLines, newLines)
+	return toEdits(ops, oldLines, newLines)
+}
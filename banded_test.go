@@ -0,0 +1,75 @@
+package diff_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestLinesBanded(t *testing.T) {
+	lines := func(n int) []string {
+		s := make([]string, n)
+		for i := range s {
+			s[i] = fmt.Sprintf("line %d\n", i)
+		}
+		return s
+	}
+	old := lines(50)
+	local := slices.Clone(old)
+	local[10] = "changed\n"
+	local = slices.Insert(local, 30, "new\n", "new\n")
+	local = slices.Delete(local, 40, 42)
+	moved := append(slices.Clone(old[40:]), old[:40]...)
+
+	tests := map[string]struct {
+		a, b    []string
+		window  int
+		minimal bool
+	}{
+		"Equal":               {a: old, b: old, window: 1, minimal: true},
+		"LocalChanges":        {a: old, b: local, window: 3, minimal: true},
+		"AllInserted":         {b: old, window: 1, minimal: true},
+		"AllDeleted":          {a: old, window: 1, minimal: true},
+		"LengthsDiffer":       {a: old[:10], b: local, window: 1, minimal: true},
+		"MovedBlockOutside":   {a: old, b: moved, window: 2, minimal: false},
+		"WindowCoversAll":     {a: old, b: moved, window: 100, minimal: true},
+		"MovedBlockWithin":    {a: old, b: moved, window: 10, minimal: true},
+		"CompletelyDifferent": {a: old[:5], b: []string{"x\n", "y\n"}, window: 1, minimal: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.LinesBanded(test.a, test.b, test.window)
+
+			var gotOld, gotNew []string
+			changes := 0
+			for _, e := range got {
+				if e.Op != diff.Ins {
+					gotOld = append(gotOld, e.OldLine)
+				}
+				if e.Op != diff.Del {
+					gotNew = append(gotNew, e.NewLine)
+				}
+				if e.Op != diff.Eq {
+					changes++
+				}
+			}
+			if !slices.Equal(gotOld, test.a) || !slices.Equal(gotNew, test.b) {
+				t.Fatalf("LinesBanded() edits do not transform a into b")
+			}
+
+			_, distance := diff.Metrics(test.a, test.b)
+			if test.minimal && changes != distance {
+				t.Errorf("LinesBanded() has %d changes, want minimal %d", changes, distance)
+			}
+			if !test.minimal && changes <= distance {
+				t.Errorf("LinesBanded() has %d changes, want more than minimal %d", changes, distance)
+			}
+			if test.minimal && test.window >= len(test.a)+len(test.b) && !slices.Equal(got, diff.Lines(test.a, test.b)) {
+				t.Errorf("LinesBanded() = %v, want Lines() %v", got, diff.Lines(test.a, test.b))
+			}
+		})
+	}
+}
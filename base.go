@@ -0,0 +1,70 @@
+package diff
+
+import (
+	"bufio"
+	"strings"
+)
+
+// baseLines returns the lines of base corresponding to each of the hunks of edits as described
+// by [WithBase].
+func baseLines(base []string, edits []Edit, hunks []hunk) [][]string {
+	var old []string
+	for _, e := range edits {
+		if e.Op != Ins {
+			old = append(old, e.OldLine)
+		}
+	}
+
+	// after[i] is the index following the base line matched to the last old line before i, and
+	// next[i] the index of the base line matched to the first old line at or after i.
+	after := make([]int, len(old)+1)
+	next := make([]int, len(old)+1)
+	for i := range next {
+		next[i] = len(base)
+	}
+	var b, o int
+	for _, e := range Lines(base, old) {
+		switch e.Op {
+		case Eq:
+			after[o+1] = b + 1
+			next[o] = b
+			b++
+			o++
+		case Del:
+			b++
+		case Ins:
+			after[o+1] = after[o]
+			o++
+		}
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		if next[i] == len(base) {
+			next[i] = next[i+1]
+		}
+	}
+
+	bases := make([][]string, len(hunks))
+	for i, h := range hunks {
+		start := h.startOld // number of old lines before an empty hunk
+		if h.countOld > 0 {
+			start--
+		}
+		bases[i] = base[after[start]:next[start+h.countOld]]
+	}
+	return bases
+}
+
+// writeBase writes the base lines annotating a hunk, each prefixed with '|'.
+func writeBase(w *bufio.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := w.WriteString("|" + line); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(line, "\n") {
+			if err := w.WriteByte('\n'); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
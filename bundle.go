@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PatchBundle is a reversible patch. Unlike a unified diff it records the complete old and new
+// lines of every change, so it can be applied to the old sequence and unapplied from the new
+// sequence without access to the other. A PatchBundle can be serialized using encoding/json.
+type PatchBundle struct {
+	Hunks []BundleHunk `json:"hunks"`
+}
+
+// BundleHunk is a single change of a [PatchBundle] replacing OldLines at position OldPos of the
+// old sequence with NewLines at position NewPos of the new sequence. Positions are 0-indexed,
+// which is the number of lines preceding the change.
+type BundleHunk struct {
+	OldPos   int      `json:"oldPos"`
+	OldLines []string `json:"oldLines"`
+	NewPos   int      `json:"newPos"`
+	NewLines []string `json:"newLines"`
+}
+
+// NewPatchBundle creates a [PatchBundle] from edits. Each run of changes becomes one hunk
+// without any context lines.
+func NewPatchBundle(edits []Edit) PatchBundle {
+	hunks, _ := buildHunks(edits, 0, 0, false)
+	bundle := PatchBundle{Hunks: make([]BundleHunk, 0, len(hunks))}
+	for _, h := range hunks {
+		bh := BundleHunk{
+			OldPos:   rebase(h.startOld, h.countOld, 0),
+			OldLines: []string{},
+			NewPos:   rebase(h.startNew, h.countNew, 0),
+			NewLines: []string{},
+		}
+		for _, e := range edits[h.start:h.end] {
+			switch e.Op {
+			case Del:
+				bh.OldLines = append(bh.OldLines, e.OldLine)
+			case Ins:
+				bh.NewLines = append(bh.NewLines, e.NewLine)
+			}
+		}
+		bundle.Hunks = append(bundle.Hunks, bh)
+	}
+	return bundle
+}
+
+// Apply applies the bundle to the old sequence a and returns the new sequence. It returns an
+// error if the old lines of a hunk do not match a.
+func (p PatchBundle) Apply(a []string) ([]string, error) {
+	return p.patch(a, func(h BundleHunk) (int, []string, []string) {
+		return h.OldPos, h.OldLines, h.NewLines
+	})
+}
+
+// Unapply reverts the bundle on the new sequence b and returns the old sequence. It returns an
+// error if the new lines of a hunk do not match b.
+func (p PatchBundle) Unapply(b []string) ([]string, error) {
+	return p.patch(b, func(h BundleHunk) (int, []string, []string) {
+		return h.NewPos, h.NewLines, h.OldLines
+	})
+}
+
+// patch replaces the lines from with the lines to of each hunk in src. side selects the position
+// and lines of a hunk to match and replace.
+func (p PatchBundle) patch(src []string, side func(BundleHunk) (pos int, from, to []string)) ([]string, error) {
+	var dst []string
+	var cursor int
+	for i, h := range p.Hunks {
+		pos, from, to := side(h)
+		if pos < cursor || pos+len(from) > len(src) {
+			return nil, fmt.Errorf("diff: hunk %d at line %d is out of range", i+1, pos+1)
+		}
+		if !slices.Equal(src[pos:pos+len(from)], from) {
+			return nil, fmt.Errorf("diff: hunk %d does not match at line %d", i+1, pos+1)
+		}
+		dst = append(dst, src[cursor:pos]...)
+		dst = append(dst, to...)
+		cursor = pos + len(from)
+	}
+	dst = append(dst, src[cursor:]...)
+	return dst, nil
+}
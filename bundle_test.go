@@ -0,0 +1,76 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestPatchBundle(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+	}{
+		"Modified": {
+			a: []string{"a\n", "b\n", "c\n", "d\n"},
+			b: []string{"a\n", "x\n", "c\n", "d\n", "e\n"},
+		},
+		"FromEmpty": {
+			a: nil,
+			b: []string{"a\n", "b\n"},
+		},
+		"ToEmpty": {
+			a: []string{"a\n", "b\n"},
+			b: nil,
+		},
+		"PaperExample": {
+			a: []string{"A", "B", "C", "A", "B", "B", "A"},
+			b: []string{"C", "B", "A", "B", "A", "C"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bundle := diff.NewPatchBundle(diff.Lines(test.a, test.b))
+
+			data, err := json.Marshal(bundle)
+			if err != nil {
+				t.Fatalf("json.Marshal() error: %v", err)
+			}
+			var decoded diff.PatchBundle
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("json.Unmarshal() error: %v", err)
+			}
+
+			gotB, err := decoded.Apply(test.a)
+			if err != nil {
+				t.Fatalf("Apply() error: %v", err)
+			}
+			if !slices.Equal(gotB, test.b) {
+				t.Errorf("Apply() = %q, want %q", gotB, test.b)
+			}
+			gotA, err := decoded.Unapply(test.b)
+			if err != nil {
+				t.Fatalf("Unapply() error: %v", err)
+			}
+			if !slices.Equal(gotA, test.a) {
+				t.Errorf("Unapply() = %q, want %q", gotA, test.a)
+			}
+		})
+	}
+}
+
+func TestPatchBundleMismatch(t *testing.T) {
+	bundle := diff.NewPatchBundle(diff.Lines([]string{"a\n", "b\n"}, []string{"a\n", "c\n"}))
+
+	if _, err := bundle.Apply([]string{"a\n", "x\n"}); err == nil {
+		t.Error("Apply() expected error for mismatching old lines, got nil")
+	}
+	if _, err := bundle.Apply([]string{"a\n"}); err == nil {
+		t.Error("Apply() expected error for too short input, got nil")
+	}
+	if _, err := bundle.Unapply([]string{"a\n", "b\n"}); err == nil {
+		t.Error("Unapply() expected error for mismatching new lines, got nil")
+	}
+}
@@ -0,0 +1,29 @@
+package diff
+
+// Churn quantifies how much of the edits is reordering instead of real change. An inserted line
+// is moved if it equals a deleted line, where each deleted line matches at most one inserted
+// line. The remaining inserted lines are genuinely new and the remaining deleted lines genuinely
+// removed, so moved+genuinelyNew is the number of inserted and moved+genuinelyRemoved the number
+// of deleted lines.
+func Churn(edits []Edit) (moved, genuinelyNew, genuinelyRemoved int) {
+	deleted := make(map[string]int)
+	for _, e := range edits {
+		if e.Op == Del {
+			deleted[e.OldLine]++
+			genuinelyRemoved++
+		}
+	}
+	for _, e := range edits {
+		if e.Op != Ins {
+			continue
+		}
+		if deleted[e.NewLine] > 0 {
+			deleted[e.NewLine]--
+			moved++
+			genuinelyRemoved--
+		} else {
+			genuinelyNew++
+		}
+	}
+	return moved, genuinelyNew, genuinelyRemoved
+}
@@ -0,0 +1,54 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestChurn(t *testing.T) {
+	tests := map[string]struct {
+		old, new              []string
+		moved, added, removed int
+	}{
+		"Equal": {
+			old: []string{"a", "b"},
+			new: []string{"a", "b"},
+		},
+		"ReorderedBlock": {
+			old:   []string{"a", "b", "c", "d", "e"},
+			new:   []string{"d", "e", "a", "b", "c"},
+			moved: 2,
+		},
+		"ReorderedAndChanged": {
+			old:     []string{"a", "b", "c", "x"},
+			new:     []string{"c", "a", "b", "y", "z"},
+			moved:   1,
+			added:   2,
+			removed: 1,
+		},
+		"DuplicatesMatchOnce": {
+			old:     []string{"dup", "dup", "a"},
+			new:     []string{"a", "dup"},
+			moved:   1,
+			removed: 1,
+		},
+		"OnlyNew": {
+			new:   []string{"a"},
+			added: 1,
+		},
+		"OnlyRemoved": {
+			old:     []string{"a"},
+			removed: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			moved, added, removed := diff.Churn(diff.Lines(test.old, test.new))
+			if moved != test.moved || added != test.added || removed != test.removed {
+				t.Errorf("Churn() = (%d, %d, %d), want (%d, %d, %d)", moved, added, removed, test.moved, test.added, test.removed)
+			}
+		})
+	}
+}
@@ -1,38 +1,83 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
 	"errors"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io"
+	"io/fs"
 	"os"
+	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/teleivo/diff"
+	"golang.org/x/text/unicode/norm"
 )
 
 // errFlagParse is a sentinel error indicating flag parsing failed.
 // The flag package already printed the error, so main should not print again.
 var errFlagParse = errors.New("flag parse error")
 
+// errInvalidArgument indicates a flag has an invalid value.
+var errInvalidArgument = errors.New("invalid argument")
+
+// errUsage indicates gdiff was called with the wrong number of arguments.
+var errUsage = errors.New("usage error")
+
 func main() {
-	code, err := run(os.Args, os.Stdout, os.Stderr)
+	code, err := run(os.Args, os.Stdin, os.Stdout, os.Stderr)
 	if err != nil && err != errFlagParse {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 	}
 	os.Exit(code)
 }
 
-func run(args []string, w io.Writer, wErr io.Writer) (int, error) {
+// run runs gdiff with the command-line args. A file named "-" is read from in.
+func run(args []string, in io.Reader, w io.Writer, wErr io.Writer) (int, error) {
 	flags := flag.NewFlagSet("gdiff", flag.ContinueOnError)
 	flags.SetOutput(wErr)
-	context := flags.Int("U", 3, "output NUM lines of unified context")
-	gutter := flags.Bool("gutter", false, "show line numbers and visible whitespace")
+	opts := options{warnings: wErr}
+	flags.IntVar(&opts.context, "U", 3, "output NUM lines of unified context")
+	flags.IntVar(&opts.context, "context", 3, "output NUM lines of unified context (same as -U)")
+	flags.IntVar(&opts.contextBefore, "context-before", -1, "output NUM lines of context before changes (overrides -U)")
+	flags.IntVar(&opts.contextAfter, "context-after", -1, "output NUM lines of context after changes (overrides -U)")
+	flags.BoolVar(&opts.gutter, "gutter", false, "show line numbers and visible whitespace")
+	flags.BoolVar(&opts.separateHunks, "minimal-context", false, "do not merge hunks whose context overlaps")
+	flags.BoolVar(&opts.ignoreSpaceChange, "b", false, "ignore changes in the amount of white space")
+	flags.BoolVar(&opts.stripTrailingCR, "strip-trailing-cr", false, "ignore carriage returns at the end of lines, like between LF and CRLF line endings")
+	flags.BoolVar(&opts.ignoreTabExpansion, "E", false, "ignore changes due to tab expansion")
+	flags.IntVar(&opts.tabSize, "tabsize", 8, "tab stops every NUM columns for -E")
+	flags.IntVar(&opts.ignoreColumns, "ignore-leading-columns", 0, "ignore the first NUM characters of each line")
+	flags.BoolVar(&opts.normalizeUnicode, "normalize-unicode", false, "ignore differences in Unicode normalization (NFC)")
+	gitBlob := flags.Bool("git-blob", false, "compare git blobs given as refs like HEAD:file instead of files")
+	flags.BoolVar(&opts.ignoreComments, "ignore-comments", false, "ignore changes in comments")
+	commentSyntax := flags.String("comment-syntax", "// /* */", "`comments` for -ignore-comments as LINE, START END or LINE START END")
+	flags.BoolVar(&opts.headersOnly, "headers-only", false, "only output the hunk headers")
+	flags.BoolVar(&opts.goFuncs, "go-funcs", false, "diff Go files function by function, naming the changed top-level functions in the hunk headers")
+	flags.BoolVar(&opts.showTrailingWS, "show-trailing-ws", false, "show trailing white space of changed lines as · for spaces and → for tabs")
+	flags.BoolVar(&opts.inline, "inline", false, "show each pair of a deleted and an inserted line once with a ~ marker, highlighting the changed words")
+	flags.BoolVar(&opts.check, "check", false, "only warn about lines adding trailing white space like git diff --check")
+	flags.BoolVar(&opts.reportLineEndings, "report-line-endings", false, "report files that only differ in line endings instead of diffing them")
+	flags.StringVar(&opts.colorPalette, "color-palette", "", "color `PALETTE` of 8, 256 or truecolor; detected from COLORTERM and TERM by default")
+	flags.StringVar(&opts.headerSeparator, "header-separator", "/", "show file paths in the header using `SEP` as path separator")
+	flags.BoolVar(&opts.reverse, "reverse", false, "output the diff transforming file2 into file1, like for rolling back a patch")
+	flags.BoolVar(&opts.git, "git", false, "prefix the header with a git \"diff --git a/file1 b/file2\" line and use a/ and b/ in the file names")
+	flags.Var(&opts.labels, "label", "use `LABEL` instead of the file name and time in the header; given twice for file1 and file2")
+	manifest := flags.String("batch", "", "diff each pair of files listed in `MANIFEST` as lines of file1 and file2 separated by a tab")
+	failFast := flags.Bool("fail-fast", false, "stop -batch at the first pair of files that cannot be diffed")
+	porcelain := flags.Bool("porcelain", false, "report errors in a stable machine-readable format")
 	flags.Usage = func() {
 		_, _ = fmt.Fprintln(wErr, "gdiff computes the shortest edit script between two files")
 		_, _ = fmt.Fprintln(wErr, "")
-		_, _ = fmt.Fprintln(wErr, "usage: gdiff [-U NUM] [-gutter] file1 file2")
+		_, _ = fmt.Fprintln(wErr, "usage: gdiff [flags] file1 file2")
+		_, _ = fmt.Fprintln(wErr, "       gdiff [flags] -batch manifest")
 		_, _ = fmt.Fprintln(wErr, "")
 		flags.PrintDefaults()
 	}
@@ -45,7 +90,59 @@ func run(args []string, w io.Writer, wErr io.Writer) (int, error) {
 		return 2, errFlagParse
 	}
 
-	if flags.NArg() != 2 {
+	fail := func(err error) (int, error) {
+		if *porcelain {
+			return 2, porcelainError(err)
+		}
+		return 2, err
+	}
+
+	if opts.context < 0 {
+		return fail(fmt.Errorf("%w: context %d", errInvalidArgument, opts.context))
+	}
+	// -1 is the default of -context-before and -context-after falling back to -U
+	if opts.contextBefore < -1 {
+		return fail(fmt.Errorf("%w: context-before %d", errInvalidArgument, opts.contextBefore))
+	}
+	if opts.contextAfter < -1 {
+		return fail(fmt.Errorf("%w: context-after %d", errInvalidArgument, opts.contextAfter))
+	}
+
+	if opts.tabSize < 1 {
+		return fail(fmt.Errorf("%w: tabsize %d", errInvalidArgument, opts.tabSize))
+	}
+
+	if opts.ignoreColumns < 0 {
+		return fail(fmt.Errorf("%w: ignore-leading-columns %d", errInvalidArgument, opts.ignoreColumns))
+	}
+
+	var ok bool
+	if opts.commentSyntax, ok = parseCommentSyntax(*commentSyntax); !ok {
+		return fail(fmt.Errorf("%w: comment syntax %q", errInvalidArgument, *commentSyntax))
+	}
+
+	switch opts.colorPalette {
+	case "", "8", "256", "truecolor":
+	default:
+		return fail(fmt.Errorf("%w: color palette %q", errInvalidArgument, opts.colorPalette))
+	}
+
+	if len(opts.labels) > 2 {
+		return fail(fmt.Errorf("%w: label given %d times", errInvalidArgument, len(opts.labels)))
+	}
+
+	if *manifest != "" && (*gitBlob || len(opts.labels) > 0) {
+		return fail(fmt.Errorf("%w: batch cannot be combined with git-blob or label", errInvalidArgument))
+	}
+
+	wantArgs := 2
+	if *manifest != "" {
+		wantArgs = 0
+	}
+	if flags.NArg() != wantArgs {
+		if *porcelain {
+			return fail(fmt.Errorf("%w: expected %d files, got %d", errUsage, wantArgs, flags.NArg()))
+		}
 		flags.Usage()
 		return 2, nil
 	}
@@ -53,9 +150,22 @@ func run(args []string, w io.Writer, wErr io.Writer) (int, error) {
 	oldFile := flags.Arg(0)
 	newFile := flags.Arg(1)
 
-	hasDiff, err := files(w, oldFile, newFile, *context, *gutter)
+	var hasDiff bool
+	switch {
+	case *manifest != "":
+		hasDiff, err = batch(w, in, *manifest, *failFast, opts, func(err error) {
+			if *porcelain {
+				err = porcelainError(err)
+			}
+			_, _ = fmt.Fprintf(wErr, "%v\n", err)
+		})
+	case *gitBlob:
+		hasDiff, err = blobs(w, oldFile, newFile, opts)
+	default:
+		hasDiff, err = files(w, in, oldFile, newFile, opts)
+	}
 	if err != nil {
-		return 2, err
+		return fail(err)
 	}
 	if hasDiff {
 		return 1, nil
@@ -63,26 +173,301 @@ func run(args []string, w io.Writer, wErr io.Writer) (int, error) {
 	return 0, nil
 }
 
-func files(w io.Writer, oldFile, newFile string, context int, gutter bool) (bool, error) {
-	oldStat, err := os.Stat(oldFile)
+// porcelainError converts err into an error with a stable machine-readable message of the form
+// "error: KIND key=value" for scripts. KIND is one of file-not-found, permission-denied,
+// io-error, invalid-argument, usage or internal. Errors on files carry the path, other errors
+// a message.
+func porcelainError(err error) error {
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &pathErr):
+		kind := "io-error"
+		if errors.Is(err, fs.ErrNotExist) {
+			kind = "file-not-found"
+		} else if errors.Is(err, fs.ErrPermission) {
+			kind = "permission-denied"
+		}
+		return fmt.Errorf("error: %s path=%q", kind, pathErr.Path)
+	case errors.Is(err, errInvalidArgument):
+		return fmt.Errorf("error: invalid-argument msg=%q", err.Error())
+	case errors.Is(err, errUsage):
+		return fmt.Errorf("error: usage msg=%q", err.Error())
+	default:
+		return fmt.Errorf("error: internal msg=%q", err.Error())
+	}
+}
+
+// options holds the command-line options controlling how files are compared and written.
+type options struct {
+	context            int
+	contextBefore      int // -1 to use context
+	contextAfter       int // -1 to use context
+	gutter             bool
+	separateHunks      bool
+	ignoreSpaceChange  bool
+	stripTrailingCR    bool
+	ignoreTabExpansion bool
+	tabSize            int
+	ignoreColumns      int
+	normalizeUnicode   bool
+	reportLineEndings  bool
+	check              bool
+	headersOnly        bool
+	ignoreComments     bool
+	commentSyntax      diff.CommentSyntax
+	headerSeparator    string // path separator in the header, empty for "/"
+	colorPalette       string // 8, 256 or truecolor, empty to detect it
+	labels             labels
+	goFuncs            bool
+	inline             bool
+	showTrailingWS     bool
+	git                bool      // git style header
+	reverse            bool      // diff file2 against file1
+	warnings           io.Writer // receives warnings, discarded if nil
+}
+
+// parseCommentSyntax parses comment syntax given as a line comment prefix, block comment
+// delimiters or both separated by white space.
+func parseCommentSyntax(s string) (diff.CommentSyntax, bool) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		return diff.CommentSyntax{Line: fields[0]}, true
+	case 2:
+		return diff.CommentSyntax{BlockStart: fields[0], BlockEnd: fields[1]}, true
+	case 3:
+		return diff.CommentSyntax{Line: fields[0], BlockStart: fields[1], BlockEnd: fields[2]}, true
+	default:
+		return diff.CommentSyntax{}, false
+	}
+}
+
+// labels collects the values of the repeatable -label flag.
+type labels []string
+
+func (l *labels) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *labels) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// label returns the label of the file at index i, or def if it has none.
+func (l labels) label(i int, def string) string {
+	if i < len(l) {
+		return l[i]
+	}
+	return def
+}
+
+// files writes the unified diff of oldFile and newFile. A file named "-" is read from in and
+// labeled "-" in the header instead of carrying a time.
+func files(w io.Writer, in io.Reader, oldFile, newFile string, opts options) (bool, error) {
+	oldHeader, a, err := readFile(in, oldFile, opts.headerSeparator)
 	if err != nil {
 		return false, err
 	}
-	newStat, err := os.Stat(newFile)
+	newHeader, b := oldHeader, a
+	if oldFile != "-" || newFile != "-" { // stdin can only be read once
+		newHeader, b, err = readFile(in, newFile, opts.headerSeparator)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if isBinary(a) || isBinary(b) {
+		return writeBinary(w, a, b, opts.labels.label(0, oldFile), opts.labels.label(1, newFile), opts)
+	}
+
+	if opts.reportLineEndings {
+		oldData, newData := []byte(strings.Join(a, "")), []byte(strings.Join(b, ""))
+		if diff.OnlyLineEndingsDiffer(oldData, newData) {
+			return true, writeLineEndings(w, oldFile, oldData, newFile, newData)
+		}
+	}
+
+	return write(w, a, b, opts.labels.label(1, newFile), opts, func() error {
+		return writeHeader(w, opts, oldFile, oldHeader, newFile, newHeader)
+	})
+}
+
+// batch writes the unified diffs of the pairs of files listed in the manifest, one pair per line
+// as file1 and file2 separated by a tab. Blank lines are skipped. A pair that cannot be diffed,
+// like one with a missing file, is reported and skipped unless failFast is set, in which case its
+// error is returned. It reports whether any pair differs and returns an error if any pair failed.
+func batch(w io.Writer, in io.Reader, manifest string, failFast bool, opts options, report func(error)) (bool, error) {
+	data, err := os.ReadFile(manifest)
 	if err != nil {
 		return false, err
 	}
+	var pairs [][2]string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		oldFile, newFile, ok := strings.Cut(line, "\t")
+		if !ok {
+			return false, fmt.Errorf("%w: %s:%d: expected file1 and file2 separated by a tab", errInvalidArgument, manifest, i+1)
+		}
+		pairs = append(pairs, [2]string{oldFile, newFile})
+	}
 
-	a, err := readLines(oldFile)
+	var hasDiff bool
+	var failed int
+	for _, pair := range pairs {
+		differs, err := files(w, in, pair[0], pair[1], opts)
+		if err != nil {
+			if failFast {
+				return hasDiff, err
+			}
+			report(err)
+			failed++
+			continue
+		}
+		hasDiff = hasDiff || differs
+	}
+	if failed > 0 {
+		return hasDiff, fmt.Errorf("batch: %d of %d pairs failed", failed, len(pairs))
+	}
+	return hasDiff, nil
+}
+
+// readFile reads the lines of the named file, or of in if the name is "-". It also returns the
+// header of the file, which shows the name with sep as path separator unless sep is empty.
+func readFile(in io.Reader, name, sep string) (string, []string, error) {
+	if name == "-" {
+		lines, err := diff.ReadLines(in)
+		if err != nil {
+			return "", nil, err
+		}
+		return name, lines, nil
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return "", nil, err
+	}
+	lines, err := diff.ReadLines(f)
+	if err != nil {
+		return "", nil, err
+	}
+	display := name
+	if sep != "" {
+		display = strings.ReplaceAll(name, "/", sep)
+	}
+	return fileHeader(display, stat.ModTime()), lines, nil
+}
+
+// blobs writes the unified diff of the git blobs named by oldRef and newRef using the refs as
+// header labels. The blobs are read using git cat-file so the files need not be checked out.
+func blobs(w io.Writer, oldRef, newRef string, opts options) (bool, error) {
+	a, err := readBlob(oldRef)
 	if err != nil {
 		return false, err
 	}
-	b, err := readLines(newFile)
+	b, err := readBlob(newRef)
 	if err != nil {
 		return false, err
 	}
+	if isBinary(a) || isBinary(b) {
+		return writeBinary(w, a, b, opts.labels.label(0, oldRef), opts.labels.label(1, newRef), opts)
+	}
+
+	return write(w, a, b, opts.labels.label(1, newRef), opts, func() error {
+		return writeHeader(w, opts, blobPath(oldRef), oldRef, blobPath(newRef), newRef)
+	})
+}
+
+// isBinary reports whether the content split into lines looks binary as by [diff.IsBinary].
+func isBinary(lines []string) bool {
+	return diff.IsBinary([]byte(strings.Join(lines, "")))
+}
+
+// writeBinary writes that the binary contents a and b named oldName and newName differ like GNU
+// diff, instead of diffing their meaningless lines. It reports whether they differ.
+func writeBinary(w io.Writer, a, b []string, oldName, newName string, opts options) (bool, error) {
+	if slices.Equal(a, b) {
+		return false, nil
+	}
+	if opts.reverse {
+		oldName, newName = newName, oldName
+	}
+	_, err := fmt.Fprintf(w, "Binary files %s and %s differ\n", oldName, newName)
+	return true, err
+}
+
+// write diffs a and b and writes the result to w. The header is written before the hunks
+// unless the gutter format is used. With the check option, only the whitespace issues of b named
+// newName are written. With the go-funcs option, the lines outside of functions and each changed
+// function of the Go files a and b are diffed separately, falling back to a line diff with a
+// warning if either fails to parse. With the
+// reverse option, the diff transforms b into a. It reports whether a and b differ, or with the
+// check option whether there are issues.
+func write(w io.Writer, a, b []string, newName string, opts options, header func() error) (bool, error) {
+	var lopts []diff.LinesOption
+	if opts.ignoreSpaceChange {
+		lopts = append(lopts, diff.WithIgnoreSpaceChange())
+	}
+	if opts.stripTrailingCR {
+		lopts = append(lopts, diff.WithStripTrailingCR())
+	}
+	if opts.ignoreTabExpansion {
+		lopts = append(lopts, diff.WithIgnoreTabExpansion(opts.tabSize))
+	}
+	if opts.ignoreComments {
+		lopts = append(lopts, diff.WithIgnoreComments(opts.commentSyntax))
+	}
+	if opts.ignoreColumns > 0 {
+		lopts = append(lopts, diff.WithIgnoreLeadingColumns(opts.ignoreColumns))
+	}
+	if opts.normalizeUnicode {
+		lopts = append(lopts, diff.WithUnicodeNormalization(norm.NFC))
+	}
+
+	oldLines, newLines := a, b
+	if opts.reverse {
+		oldLines, newLines = b, a
+	}
 
-	edits := diff.Lines(a, b)
+	if opts.goFuncs && !opts.check {
+		spans, err := changedGoFuncs(oldLines, newLines, lopts)
+		if err == nil {
+			if len(spans) == 0 {
+				return false, nil
+			}
+			return true, writeGoFuncs(w, spans, opts, header)
+		}
+		if opts.warnings != nil {
+			_, _ = fmt.Fprintf(opts.warnings, "gdiff: go-funcs: %v, falling back to a line diff\n", err)
+		}
+	}
+
+	edits := diff.Lines(a, b, lopts...)
+	if opts.reverse {
+		edits = diff.ReversePatch(edits)
+	}
+	if opts.check {
+		issues := diff.CheckWhitespace(edits)
+		for _, issue := range issues {
+			if _, err := fmt.Fprintf(w, "%s:%s\n+%s", newName, issue, issue.Content); err != nil {
+				return false, err
+			}
+			if !strings.HasSuffix(issue.Content, "\n") {
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return false, err
+				}
+			}
+		}
+		return len(issues) > 0, nil
+	}
 
 	hasDiff := false
 	for _, e := range edits {
@@ -95,44 +480,525 @@ func files(w io.Writer, oldFile, newFile string, context int, gutter bool) (bool
 		return false, nil
 	}
 
-	opts := []diff.Option{diff.WithContext(context)}
-	if gutter {
-		opts = append(opts, diff.WithGutter())
-	} else {
-		if err := writeFileHeader(w, oldFile, oldStat.ModTime(), newFile, newStat.ModTime()); err != nil {
+	if opts.inline && !opts.gutter && !opts.headersOnly {
+		if err := header(); err != nil {
 			return false, err
 		}
+		return true, writeInline(w, oldLines, newLines, lopts, opts)
 	}
-	if _, noColor := os.LookupEnv("NO_COLOR"); !noColor {
-		opts = append(opts, diff.WithColor())
+
+	wopts := writeOptions(opts)
+	if opts.gutter && !opts.headersOnly {
+		wopts = append(wopts, diff.WithGutter())
+	} else {
+		if err := header(); err != nil {
+			return false, err
+		}
 	}
-	if err := diff.Write(w, edits, opts...); err != nil {
+	if err := diff.Write(w, edits, wopts...); err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-func readLines(path string) ([]string, error) {
-	data, err := os.ReadFile(path)
+// writeOptions returns the options for writing the unified diff shared by all output modes.
+func writeOptions(opts options) []diff.Option {
+	wopts := []diff.Option{diff.WithContext(opts.context)}
+	if opts.contextBefore >= 0 {
+		wopts = append(wopts, diff.WithContextBefore(opts.contextBefore))
+	}
+	if opts.contextAfter >= 0 {
+		wopts = append(wopts, diff.WithContextAfter(opts.contextAfter))
+	}
+	if opts.separateHunks {
+		wopts = append(wopts, diff.WithSeparateHunks())
+	}
+	if opts.headersOnly {
+		wopts = append(wopts, diff.WithHeadersOnly())
+	}
+	if opts.showTrailingWS {
+		wopts = append(wopts, diff.WithShowTrailingWhitespace())
+	}
+	if _, noColor := os.LookupEnv("NO_COLOR"); !noColor {
+		del, ins := paletteColors(opts.colorPalette)
+		wopts = append(wopts, diff.WithColors(del, ins))
+	}
+	return wopts
+}
+
+// writeInline writes the hunks of the diff of a and b with -U lines of context in inline format.
+// A deleted line paired with an inserted line as by [diff.LinesWithWords] is written once with a
+// ~ marker, its unchanged words plain and its deleted and inserted words highlighted in color, or
+// enclosed in [-...-] and {+...+} like git diff --word-diff=plain if NO_COLOR is set. The
+// inserted line of a pair is not written. Other lines keep their unified markers. A line ending a
+// side without a trailing newline is followed by "\ No newline at end of file" like in unified
+// format.
+func writeInline(w io.Writer, a, b []string, lopts []diff.LinesOption, opts options) error {
+	lineEdits := diff.LinesWithWords(a, b, lopts...)
+	edits := make([]diff.Edit, len(lineEdits))
+	var changed []diff.LineEdit // changed lines in the order they are visited
+	for i, le := range lineEdits {
+		edits[i] = le.Edit
+		if le.Op != diff.Eq {
+			changed = append(changed, le)
+		}
+	}
+
+	var del, ins, reset string
+	delStart, delEnd, insStart, insEnd, mark := "[-", "-]", "{+", "+}", "~"
+	if _, noColor := os.LookupEnv("NO_COLOR"); !noColor {
+		del, ins = paletteColors(opts.colorPalette)
+		reset = "\033[0m"
+		delStart, delEnd, insStart, insEnd = del, reset, ins, reset
+		mark = "\033[33m~" + reset
+	}
+
+	// group into hunks like the other output modes
+	wopts := writeOptions(opts)
+	hunks := diff.Hunks(edits, opts.context, wopts...)
+	var sb strings.Builder
+	var err error
+	next := 0 // index into changed of the next changed line
+	diff.Walk(edits, opts.context, func(e diff.LineEvent) {
+		if err != nil {
+			return
+		}
+		sb.Reset()
+		if e.First {
+			sb.WriteString(hunks[e.Hunk].Header() + "\n")
+		}
+		var words []diff.Edit
+		if e.Op != diff.Eq {
+			words = changed[next].Words
+			next++
+		}
+		// a side without a trailing newline is marked like diff.Write does
+		noNewline := !strings.HasSuffix(e.Line, "\n")
+		switch {
+		case e.Op == diff.Eq:
+			sb.WriteString(" " + e.Line)
+		case words == nil && e.Op == diff.Del:
+			sb.WriteString(del + "-" + e.Line + reset)
+		case words == nil:
+			sb.WriteString(ins + "+" + e.Line + reset)
+		case e.Op == diff.Ins: // written with its deleted line
+			noNewline = false
+		default:
+			sb.WriteString(mark)
+			for i := 0; i < len(words); {
+				// highlight runs of deleted and inserted words as a whole
+				op := words[i].Op
+				var span strings.Builder
+				for ; i < len(words) && words[i].Op == op; i++ {
+					if op == diff.Del {
+						span.WriteString(words[i].OldLine)
+					} else {
+						span.WriteString(words[i].NewLine)
+					}
+				}
+				switch op {
+				case diff.Eq:
+					sb.WriteString(span.String())
+				case diff.Del:
+					sb.WriteString(delStart + strings.TrimSuffix(span.String(), "\n") + delEnd)
+				case diff.Ins:
+					sb.WriteString(insStart + strings.TrimSuffix(span.String(), "\n") + insEnd)
+				}
+			}
+			if !slices.ContainsFunc(words, func(w diff.Edit) bool { return w.Op != diff.Del && strings.HasSuffix(w.NewLine, "\n") }) {
+				noNewline = true // the inserted line of the pair
+			}
+			if !strings.HasSuffix(sb.String(), "\n") {
+				sb.WriteString("\n")
+			}
+		}
+		if noNewline {
+			if !strings.HasSuffix(sb.String(), "\n") {
+				sb.WriteString("\n")
+			}
+			sb.WriteString("\\ No newline at end of file\n")
+		}
+		_, err = io.WriteString(w, sb.String())
+	}, wopts...)
+	return err
+}
+
+// goFunc is a top-level function or method of a Go file.
+type goFunc struct {
+	key       string   // name qualified by the receiver type for methods
+	signature string   // first line of the declaration
+	start     int      // number of lines in the file preceding the lines of the function
+	lines     []string // lines of the function including its doc comment and preceding blank lines
+}
+
+// lineNos returns the 0-indexed line numbers of the lines of f.
+func (f goFunc) lineNos() []int {
+	lineNos := make([]int, len(f.lines))
+	for i := range lineNos {
+		lineNos[i] = f.start + i
+	}
+	return lineNos
+}
+
+// goFuncChange is a part of two Go files that may differ between them, either a top-level
+// function or the lines outside of functions. The line numbers hold the 0-indexed line in the file
+// of each line of the part. The anchors hold the number of lines in the file preceding the part,
+// which places a part without lines on a side, like an added or removed function.
+type goFuncChange struct {
+	signature              string // empty for the lines outside of functions
+	old, new               []string
+	oldLineNos, newLineNos []int
+	oldAnchor, newAnchor   int
+}
+
+// spans diffs the lines of c and splits the edits wherever consecutive lines of a side are not
+// adjacent in the file, like lines outside of functions that are separated by a function, so that
+// no context is shown as adjacent that is not. It returns the spans that differ.
+func (c goFuncChange) spans(lopts []diff.LinesOption) []goFuncSpan {
+	var spans []goFuncSpan
+	span := goFuncSpan{signature: c.signature, oldAnchor: c.oldAnchor, newAnchor: c.newAnchor}
+	flush := func() {
+		if len(span.oldLineNos) > 0 {
+			span.oldAnchor = span.oldLineNos[0]
+		}
+		if len(span.newLineNos) > 0 {
+			span.newAnchor = span.newLineNos[0]
+		}
+		if slices.ContainsFunc(span.edits, func(e diff.Edit) bool { return e.Op != diff.Eq }) {
+			spans = append(spans, span)
+		}
+		oldEnd, newEnd := span.end()
+		span = goFuncSpan{signature: c.signature, oldAnchor: oldEnd, newAnchor: newEnd}
+	}
+	// adjacent reports whether the next line of a side follows the last line of the span
+	adjacent := func(spanLineNos []int, lineNo int) bool {
+		return len(spanLineNos) == 0 || spanLineNos[len(spanLineNos)-1]+1 == lineNo
+	}
+
+	var x, y int
+	for _, e := range diff.Lines(c.old, c.new, lopts...) {
+		if (e.Op != diff.Ins && !adjacent(span.oldLineNos, c.oldLineNos[x])) ||
+			(e.Op != diff.Del && !adjacent(span.newLineNos, c.newLineNos[y])) {
+			flush()
+		}
+		span.edits = append(span.edits, e)
+		if e.Op != diff.Ins {
+			span.oldLineNos = append(span.oldLineNos, c.oldLineNos[x])
+			x++
+		}
+		if e.Op != diff.Del {
+			span.newLineNos = append(span.newLineNos, c.newLineNos[y])
+			y++
+		}
+	}
+	flush()
+	return spans
+}
+
+// goFuncSpan is a piece of a [goFuncChange] whose lines are consecutive in both files. The line
+// numbers and anchors are those of the goFuncChange restricted to the span.
+type goFuncSpan struct {
+	signature              string
+	edits                  []diff.Edit
+	oldLineNos, newLineNos []int
+	oldAnchor, newAnchor   int
+}
+
+// end returns the number of lines in the files preceding the line after the span.
+func (s goFuncSpan) end() (oldEnd, newEnd int) {
+	oldEnd, newEnd = s.oldAnchor, s.newAnchor
+	if n := len(s.oldLineNos); n > 0 {
+		oldEnd = s.oldLineNos[n-1] + 1
+	}
+	if n := len(s.newLineNos); n > 0 {
+		newEnd = s.newLineNos[n-1] + 1
+	}
+	return oldEnd, newEnd
+}
+
+// hunkHeader returns the header of a hunk of the span with line numbers relative to the files and
+// the signature of the function appended.
+func (s goFuncSpan) hunkHeader(oldStart, oldCount, newStart, newCount int) string {
+	fileLine := func(lineNos []int, anchor, start int) int {
+		if start == 0 {
+			return anchor
+		}
+		return lineNos[start-1] + 1
+	}
+	header := diff.Hunk{
+		OldStart: fileLine(s.oldLineNos, s.oldAnchor, oldStart),
+		OldCount: oldCount,
+		NewStart: fileLine(s.newLineNos, s.newAnchor, newStart),
+		NewCount: newCount,
+	}.Header()
+	if s.signature != "" {
+		header += " " + s.signature
+	}
+	return header
+}
+
+// changedGoFuncs returns the spans that differ between the Go files a and b in the order of b:
+// those of the top-level functions and of the lines outside of functions. Functions are matched
+// by name and receiver type and compared by source text, including the blank lines separating
+// them from the preceding declaration. A function only in one file is placed in the other after
+// the function preceding it that is in both, or before the first function if there is none. It
+// returns an error if either file fails to parse.
+func changedGoFuncs(a, b []string, lopts []diff.LinesOption) ([]goFuncSpan, error) {
+	oldFuncs, err := parseGoFuncs(a)
+	if err != nil {
+		return nil, err
+	}
+	newFuncs, err := parseGoFuncs(b)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := goFuncChange{}
+	rest.old, rest.oldLineNos = outsideGoFuncs(a, oldFuncs)
+	rest.new, rest.newLineNos = outsideGoFuncs(b, newFuncs)
+	changes := []goFuncChange{rest}
+
+	for i, nf := range newFuncs {
+		j := slices.IndexFunc(oldFuncs, func(of goFunc) bool { return of.key == nf.key })
+		if j < 0 {
+			changes = append(changes, goFuncChange{
+				signature:  nf.signature,
+				new:        nf.lines,
+				newLineNos: nf.lineNos(),
+				oldAnchor:  goFuncAnchor(newFuncs[:i], oldFuncs),
+				newAnchor:  nf.start,
+			})
+			continue
+		}
+		of := oldFuncs[j]
+		if !slices.Equal(of.lines, nf.lines) {
+			changes = append(changes, goFuncChange{
+				signature:  nf.signature,
+				old:        of.lines,
+				new:        nf.lines,
+				oldLineNos: of.lineNos(),
+				newLineNos: nf.lineNos(),
+				oldAnchor:  of.start,
+				newAnchor:  nf.start,
+			})
+		}
+	}
+	for i, of := range oldFuncs {
+		if !slices.ContainsFunc(newFuncs, func(nf goFunc) bool { return nf.key == of.key }) {
+			changes = append(changes, goFuncChange{
+				signature:  of.signature,
+				old:        of.lines,
+				oldLineNos: of.lineNos(),
+				oldAnchor:  of.start,
+				newAnchor:  goFuncAnchor(oldFuncs[:i], newFuncs),
+			})
+		}
+	}
+
+	var spans []goFuncSpan
+	for _, c := range changes {
+		spans = append(spans, c.spans(lopts)...)
+	}
+	slices.SortStableFunc(spans, func(s, t goFuncSpan) int {
+		return cmp.Or(cmp.Compare(s.newAnchor, t.newAnchor), cmp.Compare(s.oldAnchor, t.oldAnchor))
+	})
+	return spans, nil
+}
+
+// goFuncAnchor returns the number of lines of the file of funcs preceding the place of a function
+// that follows preceding in the other file: the end of the last of preceding that is in funcs, or
+// the start of the first of funcs if there is none.
+func goFuncAnchor(preceding, funcs []goFunc) int {
+	for i := len(preceding) - 1; i >= 0; i-- {
+		if j := slices.IndexFunc(funcs, func(f goFunc) bool { return f.key == preceding[i].key }); j >= 0 {
+			return funcs[j].start + len(funcs[j].lines)
+		}
+	}
+	if len(funcs) > 0 {
+		return funcs[0].start
+	}
+	return 0
+}
+
+// outsideGoFuncs returns the lines of the Go file outside of its functions funcs together with
+// their 0-indexed line numbers.
+func outsideGoFuncs(lines []string, funcs []goFunc) (rest []string, lineNos []int) {
+	for i, line := range lines {
+		if !slices.ContainsFunc(funcs, func(f goFunc) bool { return i >= f.start && i < f.start+len(f.lines) }) {
+			rest = append(rest, line)
+			lineNos = append(lineNos, i)
+		}
+	}
+	return rest, lineNos
+}
+
+// parseGoFuncs parses the Go file of lines and returns its top-level functions. The blank lines
+// separating a function from the preceding declaration belong to the function.
+func parseGoFuncs(lines []string) ([]goFunc, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", strings.Join(lines, ""), parser.ParseComments|parser.SkipObjectResolution)
 	if err != nil {
 		return nil, err
 	}
-	if len(data) == 0 {
-		return nil, nil
+	var funcs []goFunc
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		key := fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			key = receiverType(fn.Recv.List[0].Type) + "." + key
+		}
+		pos := fn.Pos()
+		if fn.Doc != nil {
+			pos = fn.Doc.Pos()
+		}
+		start := fset.Position(pos).Line - 1
+		for start > 0 && strings.TrimSpace(lines[start-1]) == "" {
+			start--
+		}
+		end := fset.Position(fn.End()).Line
+		funcs = append(funcs, goFunc{
+			key:       key,
+			signature: strings.TrimSpace(lines[fset.Position(fn.Pos()).Line-1]),
+			start:     start,
+			lines:     lines[start:end],
+		})
+	}
+	return funcs, nil
+}
+
+// receiverType returns the name of the type of a method receiver without pointer and type
+// parameters.
+func receiverType(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// writeGoFuncs writes the unified diff of each span with the signature of its function in its
+// hunk headers and line numbers relative to the files. The header is written before the first span
+// unless the gutter format is used.
+func writeGoFuncs(w io.Writer, spans []goFuncSpan, opts options, header func() error) error {
+	if !opts.gutter || opts.headersOnly {
+		if err := header(); err != nil {
+			return err
+		}
+	}
+	for _, s := range spans {
+		wopts := writeOptions(opts)
+		if opts.gutter && !opts.headersOnly {
+			wopts = append(wopts, diff.WithGutter())
+		}
+		wopts = append(wopts, diff.WithHunkHeader(s.hunkHeader))
+		if err := diff.Write(w, s.edits, wopts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blobPath returns the path of the git blob named by ref like HEAD:dir/file, or ref itself if it
+// does not name a path.
+func blobPath(ref string) string {
+	if _, path, ok := strings.Cut(ref, ":"); ok {
+		return path
+	}
+	return ref
+}
+
+// readBlob reads the lines of the git blob named by ref.
+func readBlob(ref string) ([]string, error) {
+	git, err := exec.LookPath("git")
+	if err != nil {
+		return nil, errors.New("git-blob: git not found in PATH")
+	}
+	var stderr strings.Builder
+	cmd := exec.Command(git, "cat-file", "blob", ref)
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git-blob: %s: %s", ref, msg)
+		}
+		return nil, fmt.Errorf("git-blob: %s: %v", ref, err)
+	}
+	return diff.ReadLines(bytes.NewReader(data))
+}
+
+// writeLineEndings reports that the files only differ in line endings together with the number
+// of line endings of each kind per file.
+func writeLineEndings(w io.Writer, oldFile string, oldData []byte, newFile string, newData []byte) error {
+	oldLF, oldCRLF := diff.CountLineEndings(oldData)
+	newLF, newCRLF := diff.CountLineEndings(newData)
+	_, err := fmt.Fprintf(w, "Files %s and %s differ only in line endings (%s: %d LF, %d CRLF; %s: %d LF, %d CRLF)\n",
+		oldFile, newFile, oldFile, oldLF, oldCRLF, newFile, newLF, newCRLF)
+	return err
+}
+
+// paletteColors returns the escape sequences coloring deleted and inserted lines in the palette.
+// An empty palette is detected from the environment: truecolor if COLORTERM is truecolor or
+// 24bit, 256 if TERM names a 256-color terminal and 8 otherwise.
+func paletteColors(palette string) (del, ins string) {
+	if palette == "" {
+		palette = "8"
+		if colorTerm := os.Getenv("COLORTERM"); colorTerm == "truecolor" || colorTerm == "24bit" {
+			palette = "truecolor"
+		} else if strings.Contains(os.Getenv("TERM"), "256color") {
+			palette = "256"
+		}
 	}
-	// SplitAfter keeps the delimiter on each element. Files ending in "\n"
-	// produce a trailing empty string that is not a real line.
-	lines := strings.SplitAfter(string(data), "\n")
-	if lines[len(lines)-1] == "" {
-		lines = lines[:len(lines)-1]
+	switch palette {
+	case "truecolor":
+		return "\033[38;2;220;50;47m", "\033[38;2;64;160;43m"
+	case "256":
+		return "\033[38;5;160m", "\033[38;5;34m"
+	default:
+		return "\033[31m", "\033[32m"
 	}
-	return lines, nil
 }
 
-func writeFileHeader(w io.Writer, oldName string, oldTime time.Time, newName string, newTime time.Time) error {
+// fileHeader returns the header of a file consisting of its name and modification time.
+func fileHeader(name string, modTime time.Time) string {
 	const timeFormat = "2006-01-02 15:04:05.000000000 -0700"
-	_, err := fmt.Fprintf(w, "--- %s\t%s\n+++ %s\t%s\n",
-		oldName, oldTime.Format(timeFormat),
-		newName, newTime.Format(timeFormat))
+	return name + "\t" + modTime.Format(timeFormat)
+}
+
+func writeFileHeader(w io.Writer, oldHeader, newHeader string) error {
+	_, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldHeader, newHeader)
 	return err
 }
+
+// writeHeader writes the header of the diff of the files oldName and newName using their
+// headers or labels. With the git option, it is preceded by the line "diff --git a/old b/new" and
+// the headers are the names prefixed with a/ and b/. With the reverse option, the files swap
+// their roles together with their labels.
+func writeHeader(w io.Writer, opts options, oldName, oldHeader, newName, newHeader string) error {
+	oldLabel, newLabel := 0, 1
+	if opts.reverse {
+		oldName, newName = newName, oldName
+		oldHeader, newHeader = newHeader, oldHeader
+		oldLabel, newLabel = 1, 0
+	}
+	if opts.git {
+		if _, err := fmt.Fprintf(w, "diff --git a/%s b/%s\n", oldName, newName); err != nil {
+			return err
+		}
+		oldHeader, newHeader = "a/"+oldName, "b/"+newName
+	}
+	return writeFileHeader(w, opts.labels.label(oldLabel, oldHeader), opts.labels.label(newLabel, newHeader))
+}
@@ -2,6 +2,11 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -75,7 +80,7 @@ func TestFiles(t *testing.T) {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			var buf bytes.Buffer
-			hasDiff, err := files(&buf, test.a, test.b, test.context, false)
+			hasDiff, err := files(&buf, nil, test.a, test.b, options{context: test.context, contextBefore: -1, contextAfter: -1})
 			if test.wantErr {
 				if err == nil {
 					t.Fatalf("files() expected error, got nil")
@@ -102,7 +107,7 @@ func TestWriteFileHeader(t *testing.T) {
 	want := "--- a.txt\t2026-02-04 08:12:16.002963487 +0100\n+++ b.txt\t2026-02-04 09:30:45.123456789 +0100\n"
 
 	var buf bytes.Buffer
-	err := writeFileHeader(&buf, "a.txt", oldTime, "b.txt", newTime)
+	err := writeFileHeader(&buf, fileHeader("a.txt", oldTime), fileHeader("b.txt", newTime))
 	if err != nil {
 		t.Fatalf("writeFileHeader() error: %v", err)
 	}
@@ -111,3 +116,1242 @@ func TestWriteFileHeader(t *testing.T) {
 		t.Errorf("writeFileHeader() =\n%q\nwant:\n%q", got, want)
 	}
 }
+
+func TestFilesIgnoreTabExpansion(t *testing.T) {
+	dir := t.TempDir()
+	tabs := filepath.Join(dir, "tabs.txt")
+	spaces := filepath.Join(dir, "spaces.txt")
+	if err := os.WriteFile(tabs, []byte("func main() {\n\treturn\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spaces, []byte("func main() {\n        return\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		opts     options
+		wantDiff bool
+	}{
+		"Disabled": {
+			opts:     options{context: 3, contextBefore: -1, contextAfter: -1, tabSize: 8},
+			wantDiff: true,
+		},
+		"Enabled": {
+			opts:     options{context: 3, contextBefore: -1, contextAfter: -1, ignoreTabExpansion: true, tabSize: 8},
+			wantDiff: false,
+		},
+		"EnabledOtherTabSize": {
+			opts:     options{context: 3, contextBefore: -1, contextAfter: -1, ignoreTabExpansion: true, tabSize: 4},
+			wantDiff: true,
+		},
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			hasDiff, err := files(&buf, nil, tabs, spaces, test.opts)
+			if err != nil {
+				t.Fatalf("files() unexpected error: %v", err)
+			}
+			if hasDiff != test.wantDiff {
+				t.Errorf("files() hasDiff = %v, want %v", hasDiff, test.wantDiff)
+			}
+		})
+	}
+}
+
+func TestRunIgnoreSpaceChange(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("x  =  1\ny = 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("x = 1 \ny = 2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+	}{
+		"Disabled": {
+			args:     []string{"gdiff", a, b},
+			wantCode: 1,
+		},
+		"Enabled": {
+			args:     []string{"gdiff", "-b", a, b},
+			wantCode: 0,
+		},
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var w, wErr bytes.Buffer
+			code, err := run(test.args, nil, &w, &wErr)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if code != test.wantCode {
+				t.Errorf("run() code = %d, want %d", code, test.wantCode)
+			}
+		})
+	}
+}
+
+func TestRunStripTrailingCR(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("one\r\n2\r\nthree\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"Disabled": {
+			args: []string{"gdiff", "-label", "a", "-label", "b", a, b},
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n-one\n-two\n-three\n+one\r\n+2\r\n+three\r\n",
+		},
+		"Enabled": {
+			args: []string{"gdiff", "-strip-trailing-cr", "-label", "a", "-label", "b", a, b},
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n one\r\n-two\n+2\r\n three\r\n",
+		},
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var w, wErr bytes.Buffer
+			code, err := run(test.args, nil, &w, &wErr)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if code != 1 {
+				t.Errorf("run() code = %d, want 1", code)
+			}
+			if got := w.String(); got != test.want {
+				t.Errorf("run() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFilesNormalizeUnicode(t *testing.T) {
+	dir := t.TempDir()
+	nfc := filepath.Join(dir, "nfc.txt")
+	nfd := filepath.Join(dir, "nfd.txt")
+	if err := os.WriteFile(nfc, []byte("caf\u00e9\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(nfd, []byte("cafe\u0301\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		opts     options
+		wantDiff bool
+	}{
+		"Disabled": {
+			opts:     options{context: 3, contextBefore: -1, contextAfter: -1, tabSize: 8},
+			wantDiff: true,
+		},
+		"Enabled": {
+			opts:     options{context: 3, contextBefore: -1, contextAfter: -1, tabSize: 8, normalizeUnicode: true},
+			wantDiff: false,
+		},
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			hasDiff, err := files(&buf, nil, nfc, nfd, test.opts)
+			if err != nil {
+				t.Fatalf("files() unexpected error: %v", err)
+			}
+			if hasDiff != test.wantDiff {
+				t.Errorf("files() hasDiff = %v, want %v", hasDiff, test.wantDiff)
+			}
+		})
+	}
+}
+
+func TestFilesReportLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	lf := filepath.Join(dir, "lf.txt")
+	crlf := filepath.Join(dir, "crlf.txt")
+	if err := os.WriteFile(lf, []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(crlf, []byte("a\r\nb\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	opts := options{context: 3, contextBefore: -1, contextAfter: -1, tabSize: 8, reportLineEndings: true}
+	hasDiff, err := files(&buf, nil, lf, crlf, opts)
+	if err != nil {
+		t.Fatalf("files() unexpected error: %v", err)
+	}
+	if !hasDiff {
+		t.Error("files() hasDiff = false, want true")
+	}
+	want := "Files " + lf + " and " + crlf + " differ only in line endings (" + lf + ": 2 LF, 0 CRLF; " + crlf + ": 0 LF, 2 CRLF)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("files() = %q, want %q", got, want)
+	}
+}
+
+func TestRunIgnoreComments(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.sh")
+	b := filepath.Join(dir, "b.sh")
+	if err := os.WriteFile(a, []byte("# old comment\necho hi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("# new comment\necho hi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+		wantErr  bool
+	}{
+		"Disabled": {
+			args:     []string{"gdiff", a, b},
+			wantCode: 1,
+		},
+		"Enabled": {
+			args:     []string{"gdiff", "-ignore-comments", "-comment-syntax", "#", a, b},
+			wantCode: 0,
+		},
+		"OtherSyntax": {
+			args:     []string{"gdiff", "-ignore-comments", a, b},
+			wantCode: 1,
+		},
+		"InvalidSyntax": {
+			args:     []string{"gdiff", "-ignore-comments", "-comment-syntax", "", a, b},
+			wantCode: 2,
+			wantErr:  true,
+		},
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var w, wErr bytes.Buffer
+			code, err := run(test.args, nil, &w, &wErr)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("run() error = %v, want error %v", err, test.wantErr)
+			}
+			if code != test.wantCode {
+				t.Errorf("run() code = %d, want %d", code, test.wantCode)
+			}
+		})
+	}
+}
+
+func TestRunHeaderSeparator(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var w, wErr bytes.Buffer
+	code, err := run([]string{"gdiff", "-header-separator", "\\", "testdata/multi_line_a.txt", "testdata/multi_line_b.txt"}, nil, &w, &wErr)
+	if err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if code != 1 {
+		t.Errorf("run() code = %d, want 1", code)
+	}
+	want := "--- " + testFileHeader(t, "testdata/multi_line_a.txt", `testdata\multi_line_a.txt`) + "\n" +
+		"+++ " + testFileHeader(t, "testdata/multi_line_b.txt", `testdata\multi_line_b.txt`) + "\n" +
+		`@@ -1,3 +1,3 @@
+ line1
+-line2
++modified
+ line3
+`
+	if got := w.String(); got != want {
+		t.Errorf("run() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// testFileHeader returns the header gdiff writes for the file at path displayed as name, holding
+// its modification time in the local time zone.
+func testFileHeader(t *testing.T, path, name string) string {
+	t.Helper()
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("os.Stat() error: %v", err)
+	}
+	return fileHeader(name, stat.ModTime())
+}
+
+func TestRunColorPalette(t *testing.T) {
+	tests := map[string]struct {
+		args      []string
+		env       map[string]string
+		wantLines string
+	}{
+		"8": {
+			args:      []string{"gdiff", "-color-palette", "8"},
+			wantLines: "\033[31m-line2\n\033[0m\033[32m+modified\n\033[0m",
+		},
+		"256": {
+			args:      []string{"gdiff", "-color-palette", "256"},
+			wantLines: "\033[38;5;160m-line2\n\033[0m\033[38;5;34m+modified\n\033[0m",
+		},
+		"Truecolor": {
+			args:      []string{"gdiff", "-color-palette", "truecolor"},
+			wantLines: "\033[38;2;220;50;47m-line2\n\033[0m\033[38;2;64;160;43m+modified\n\033[0m",
+		},
+		"DetectTruecolor": {
+			args:      []string{"gdiff"},
+			env:       map[string]string{"COLORTERM": "truecolor", "TERM": "xterm-256color"},
+			wantLines: "\033[38;2;220;50;47m-line2\n\033[0m\033[38;2;64;160;43m+modified\n\033[0m",
+		},
+		"Detect256": {
+			args:      []string{"gdiff"},
+			env:       map[string]string{"COLORTERM": "", "TERM": "xterm-256color"},
+			wantLines: "\033[38;5;160m-line2\n\033[0m\033[38;5;34m+modified\n\033[0m",
+		},
+		"Detect8": {
+			args:      []string{"gdiff"},
+			env:       map[string]string{"COLORTERM": "", "TERM": "xterm"},
+			wantLines: "\033[31m-line2\n\033[0m\033[32m+modified\n\033[0m",
+		},
+		"NoColor": {
+			args:      []string{"gdiff", "-color-palette", "truecolor"},
+			env:       map[string]string{"NO_COLOR": "1"},
+			wantLines: "-line2\n+modified\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "") // restores NO_COLOR after unsetting it
+			os.Unsetenv("NO_COLOR")
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+			args := append(test.args, "-label", "a", "-label", "b", "testdata/multi_line_a.txt", "testdata/multi_line_b.txt")
+			var w, wErr bytes.Buffer
+			code, err := run(args, nil, &w, &wErr)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if code != 1 {
+				t.Errorf("run() code = %d, want 1", code)
+			}
+			want := "--- a\n+++ b\n@@ -1,3 +1,3 @@\n line1\n" + test.wantLines + " line3\n"
+			if got := w.String(); got != want {
+				t.Errorf("run() =\n%q\nwant:\n%q", got, want)
+			}
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		var w, wErr bytes.Buffer
+		code, err := run([]string{"gdiff", "-color-palette", "16", "testdata/empty.txt", "testdata/empty.txt"}, nil, &w, &wErr)
+		if code != 2 || err == nil {
+			t.Errorf("run() = %d, %v, want 2 and an error", code, err)
+		}
+	})
+}
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("one\ntwo  \nTHREE\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	var w, wErr bytes.Buffer
+	code, err := run([]string{"gdiff", "-check", a, b}, nil, &w, &wErr)
+	if err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if code != 1 {
+		t.Errorf("run() code = %d, want 1", code)
+	}
+	want := b + ":2: trailing whitespace.\n+two  \n"
+	if got := w.String(); got != want {
+		t.Errorf("run() = %q, want %q", got, want)
+	}
+
+	w.Reset()
+	code, err = run([]string{"gdiff", "-check", a, a}, nil, &w, &wErr)
+	if err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if code != 0 || w.Len() != 0 {
+		t.Errorf("run() = %d, %q, want 0 and no output", code, w.String())
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"Default": {
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		"U": {
+			args: []string{"-U", "1"},
+			want: "--- a\n+++ b\n@@ -4,3 +4,3 @@\n 4\n-5\n+five\n 6\n",
+		},
+		"Context": {
+			args: []string{"-context", "1"},
+			want: "--- a\n+++ b\n@@ -4,3 +4,3 @@\n 4\n-5\n+five\n 6\n",
+		},
+		"Zero": {
+			args: []string{"-U", "0"},
+			want: "--- a\n+++ b\n@@ -5 +5 @@\n-5\n+five\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "1")
+			args := append([]string{"gdiff"}, test.args...)
+			args = append(args, "-label", "a", "-label", "b", a, b)
+			var w, wErr bytes.Buffer
+			code, err := run(args, nil, &w, &wErr)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if code != 1 {
+				t.Errorf("run() code = %d, want 1", code)
+			}
+			if got := w.String(); got != test.want {
+				t.Errorf("run() = %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	for _, flag := range []string{"-context", "-context-before", "-context-after"} {
+		t.Run("Negative"+flag, func(t *testing.T) {
+			var w, wErr bytes.Buffer
+			code, err := run([]string{"gdiff", flag, "-2", a, b}, nil, &w, &wErr)
+			if !errors.Is(err, errInvalidArgument) {
+				t.Errorf("run() error = %v, want %v", err, errInvalidArgument)
+			}
+			if code != 2 {
+				t.Errorf("run() code = %d, want 2", code)
+			}
+		})
+	}
+}
+
+func TestRunHeadersOnly(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	var w, wErr bytes.Buffer
+	code, err := run([]string{"gdiff", "-headers-only", "-label", "a", "-label", "b", a, b}, nil, &w, &wErr)
+	if err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if code != 1 {
+		t.Errorf("run() code = %d, want 1", code)
+	}
+	want := "--- a\n+++ b\n@@ -1,4 +1,4 @@\n@@ -7,4 +7,4 @@\n"
+	if got := w.String(); got != want {
+		t.Errorf("run() = %q, want %q", got, want)
+	}
+}
+
+func TestRunGoFuncs(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	if err := os.WriteFile(a, []byte(`package p
+
+import "fmt"
+
+// Hello greets.
+func Hello() {
+	fmt.Println("hello")
+}
+
+func (t *T) Same() {}
+
+func Removed() {}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte(`package p
+
+import "strings"
+
+func (t *T) Same() {}
+
+// Hello greets.
+func Hello() {
+	fmt.Println("hello, world")
+}
+
+func Added() {}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ChangedFunctions", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		var w, wErr bytes.Buffer
+		code, err := run([]string{"gdiff", "-go-funcs", "-U", "1", "-label", "a", "-label", "b", a, b}, nil, &w, &wErr)
+		if err != nil {
+			t.Fatalf("run() unexpected error: %v", err)
+		}
+		if code != 1 {
+			t.Errorf("run() code = %d, want 1", code)
+		}
+		// the blank lines preceding a function belong to it. Added follows Hello, which ends at line
+		// 8 of a, and Removed follows Same, which ends at line 5 of b.
+		want := "--- a\n+++ b\n" +
+			"@@ -2,2 +2,2 @@\n \n-import \"fmt\"\n+import \"strings\"\n" +
+			"@@ -6,3 +8,3 @@ func Hello() {\n func Hello() {\n-\tfmt.Println(\"hello\")\n+\tfmt.Println(\"hello, world\")\n }\n" +
+			"@@ -11,2 +5,0 @@ func Removed() {}\n-\n-func Removed() {}\n" +
+			"@@ -8,0 +11,2 @@ func Added() {}\n+\n+func Added() {}\n"
+		if got := w.String(); got != want {
+			t.Errorf("run() =\n%s\nwant:\n%s", got, want)
+		}
+		if wErr.Len() != 0 {
+			t.Errorf("run() unexpected warning: %q", wErr.String())
+		}
+	})
+
+	t.Run("OnlyOutsideFunctions", func(t *testing.T) {
+		c := filepath.Join(dir, "c.go")
+		if err := os.WriteFile(c, []byte("package p\n\nvar x = 1\n\nfunc F() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		d := filepath.Join(dir, "d.go")
+		if err := os.WriteFile(d, []byte("package p\n\nvar x = 2\n\nfunc F() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("NO_COLOR", "1")
+		var w, wErr bytes.Buffer
+		code, err := run([]string{"gdiff", "-go-funcs", "-U", "0", "-label", "c", "-label", "d", c, d}, nil, &w, &wErr)
+		if err != nil {
+			t.Fatalf("run() unexpected error: %v", err)
+		}
+		if code != 1 {
+			t.Errorf("run() code = %d, want 1", code)
+		}
+		want := "--- c\n+++ d\n@@ -3 +3 @@\n-var x = 1\n+var x = 2\n"
+		if got := w.String(); got != want {
+			t.Errorf("run() =\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("AddedBeforeAllFunctions", func(t *testing.T) {
+		c := filepath.Join(dir, "c.go")
+		if err := os.WriteFile(c, []byte("package p\n\nfunc F() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		d := filepath.Join(dir, "d.go")
+		if err := os.WriteFile(d, []byte("package p\n\nfunc E() {}\n\nfunc F() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("NO_COLOR", "1")
+		var w, wErr bytes.Buffer
+		code, err := run([]string{"gdiff", "-go-funcs", "-U", "0", "-label", "c", "-label", "d", c, d}, nil, &w, &wErr)
+		if err != nil {
+			t.Fatalf("run() unexpected error: %v", err)
+		}
+		if code != 1 {
+			t.Errorf("run() code = %d, want 1", code)
+		}
+		// E is placed before F, which is preceded by 1 line in c
+		want := "--- c\n+++ d\n@@ -1,0 +2,2 @@ func E() {}\n+\n+func E() {}\n"
+		if got := w.String(); got != want {
+			t.Errorf("run() =\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("InFileOrder", func(t *testing.T) {
+		c := filepath.Join(dir, "c.go")
+		if err := os.WriteFile(c, []byte(`package p
+
+import "fmt"
+
+// Hello greets.
+func Hello() {
+	fmt.Println("hello")
+}
+
+func Removed() {}
+`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		d := filepath.Join(dir, "d.go")
+		if err := os.WriteFile(d, []byte(`package p
+
+import "fmt"
+
+func New() {}
+
+// Hello greets.
+func Hello() {
+	fmt.Println("hello, world")
+}
+`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := map[string]struct {
+			context string
+			want    string
+		}{
+			"DefaultContext": {
+				context: "3",
+				want: "--- c\n+++ d\n" +
+					"@@ -3,0 +4,2 @@ func New() {}\n+\n+func New() {}\n" +
+					"@@ -4,5 +6,5 @@ func Hello() {\n \n // Hello greets.\n func Hello() {\n-\tfmt.Println(\"hello\")\n+\tfmt.Println(\"hello, world\")\n }\n" +
+					"@@ -9,2 +10,0 @@ func Removed() {}\n-\n-func Removed() {}\n",
+			},
+			"NoContext": {
+				context: "0",
+				want: "--- c\n+++ d\n" +
+					"@@ -3,0 +4,2 @@ func New() {}\n+\n+func New() {}\n" +
+					"@@ -7 +9 @@ func Hello() {\n-\tfmt.Println(\"hello\")\n+\tfmt.Println(\"hello, world\")\n" +
+					"@@ -9,2 +10,0 @@ func Removed() {}\n-\n-func Removed() {}\n",
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Setenv("NO_COLOR", "1")
+				var w, wErr bytes.Buffer
+				code, err := run([]string{"gdiff", "-go-funcs", "-U", test.context, "-label", "c", "-label", "d", c, d}, nil, &w, &wErr)
+				if err != nil {
+					t.Fatalf("run() unexpected error: %v", err)
+				}
+				if code != 1 {
+					t.Errorf("run() code = %d, want 1", code)
+				}
+				if got := w.String(); got != test.want {
+					t.Errorf("run() =\n%s\nwant:\n%s", got, test.want)
+				}
+			})
+		}
+	})
+
+	t.Run("OutsideFunctionsNotAdjacent", func(t *testing.T) {
+		c := filepath.Join(dir, "c.go")
+		if err := os.WriteFile(c, []byte("package p\n\nvar x = 1\n\nfunc F() {}\n\nvar y = 1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		d := filepath.Join(dir, "d.go")
+		if err := os.WriteFile(d, []byte("package p\n\nvar x = 2\n\nfunc F() {}\n\nvar y = 1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("NO_COLOR", "1")
+		var w, wErr bytes.Buffer
+		code, err := run([]string{"gdiff", "-go-funcs", "-label", "c", "-label", "d", c, d}, nil, &w, &wErr)
+		if err != nil {
+			t.Fatalf("run() unexpected error: %v", err)
+		}
+		if code != 1 {
+			t.Errorf("run() code = %d, want 1", code)
+		}
+		// var y is separated from var x by F, so it is no context of the change
+		want := "--- c\n+++ d\n@@ -1,3 +1,3 @@\n package p\n \n-var x = 1\n+var x = 2\n"
+		if got := w.String(); got != want {
+			t.Errorf("run() =\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("FallbackOnParseError", func(t *testing.T) {
+		c := filepath.Join(dir, "c.go")
+		if err := os.WriteFile(c, []byte("package p\n\nfunc Hello( {\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("NO_COLOR", "1")
+		var w, wErr bytes.Buffer
+		code, err := run([]string{"gdiff", "-go-funcs", "-headers-only", "-label", "a", "-label", "c", a, c}, nil, &w, &wErr)
+		if err != nil {
+			t.Fatalf("run() unexpected error: %v", err)
+		}
+		if code != 1 {
+			t.Errorf("run() code = %d, want 1", code)
+		}
+		want := "--- a\n+++ c\n@@ -1,12 +1,3 @@\n"
+		if got := w.String(); got != want {
+			t.Errorf("run() = %q, want %q", got, want)
+		}
+		if !strings.Contains(wErr.String(), "falling back to a line diff") {
+			t.Errorf("run() warning = %q, want it to mention the fallback", wErr.String())
+		}
+	})
+}
+
+func TestRunGit(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "x"), []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "y"), []byte("a\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"SameFile": {
+			args: []string{"gdiff", "-git", "x", "x"},
+			want: "",
+		},
+		"Files": {
+			args: []string{"gdiff", "-git", "x", "y"},
+			want: "diff --git a/x b/y\n--- a/x\n+++ b/y\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+		},
+		"Labels": {
+			args: []string{"gdiff", "-git", "-label", "old", "-label", "new", "x", "y"},
+			want: "diff --git a/x b/y\n--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "1")
+			var w, wErr bytes.Buffer
+			if _, err := run(test.args, nil, &w, &wErr); err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if got := w.String(); got != test.want {
+				t.Errorf("run() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRunInline(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("one\nthe quick brown fox\ngone\nfour\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("one\nthe slow brown fox\nfour\nfive\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args  []string
+		color bool
+		want  string
+	}{
+		"NoColor": {
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n one\n~the [-quick-]{+slow+} brown fox\n-gone\n four\n+five\n",
+		},
+		"NoContextBeforeAfter": {
+			args: []string{"-context-before", "0", "-context-after", "0"},
+			want: "--- a\n+++ b\n@@ -2,2 +2 @@\n~the [-quick-]{+slow+} brown fox\n-gone\n@@ -4,0 +4 @@\n+five\n",
+		},
+		"MinimalContext": {
+			args: []string{"-U", "1", "-minimal-context"},
+			want: "--- a\n+++ b\n@@ -1,4 +1,3 @@\n one\n~the [-quick-]{+slow+} brown fox\n-gone\n four\n@@ -4,0 +4 @@\n+five\n",
+		},
+		"Color": {
+			color: true,
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n one\n" +
+				"\033[33m~\033[0mthe \033[31mquick\033[0m\033[32mslow\033[0m brown fox\n" +
+				"\033[31m-gone\n\033[0m four\n\033[32m+five\n\033[0m",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "1")
+			if test.color {
+				os.Unsetenv("NO_COLOR")
+			}
+			var w, wErr bytes.Buffer
+			args := append([]string{"gdiff", "-inline", "-color-palette", "8"}, test.args...)
+			args = append(args, "-label", "a", "-label", "b", a, b)
+			code, err := run(args, nil, &w, &wErr)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if code != 1 {
+				t.Errorf("run() code = %d, want 1", code)
+			}
+			if got := w.String(); got != test.want {
+				t.Errorf("run() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("NoNewlineAtEndOfFile", func(t *testing.T) {
+		c := filepath.Join(dir, "c.txt")
+		if err := os.WriteFile(c, []byte("one\nthe quick fox\ngone"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		d := filepath.Join(dir, "d.txt")
+		if err := os.WriteFile(d, []byte("one\nthe slow fox"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("NO_COLOR", "1")
+		var w, wErr bytes.Buffer
+		code, err := run([]string{"gdiff", "-inline", "-label", "c", "-label", "d", c, d}, nil, &w, &wErr)
+		if err != nil {
+			t.Fatalf("run() unexpected error: %v", err)
+		}
+		if code != 1 {
+			t.Errorf("run() code = %d, want 1", code)
+		}
+		want := "--- c\n+++ d\n@@ -1,3 +1,2 @@\n one\n~the [-quick-]{+slow+} fox[--]\n\\ No newline at end of file\n-gone\n\\ No newline at end of file\n"
+		if got := w.String(); got != want {
+			t.Errorf("run() =\n%q\nwant:\n%q", got, want)
+		}
+	})
+}
+
+func TestRunIgnoreLeadingColumns(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("0001 alice\n0002 bob\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("1001 alice\n1002 bob\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	var w, wErr bytes.Buffer
+	code, err := run([]string{"gdiff", "-ignore-leading-columns", "5", a, b}, nil, &w, &wErr)
+	if err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if code != 0 {
+		t.Errorf("run() code = %d, want 0", code)
+	}
+	if w.Len() != 0 {
+		t.Errorf("run() = %q, want no output", w.String())
+	}
+
+	code, err = run([]string{"gdiff", "-ignore-leading-columns", "-1", a, b}, nil, &w, &wErr)
+	if code != 2 || !errors.Is(err, errInvalidArgument) {
+		t.Errorf("run() = (%d, %v), want (2, %v)", code, err, errInvalidArgument)
+	}
+}
+
+func TestRunReverse(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "x"), []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "y"), []byte("a\nc\nd\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"Labels": {
+			args: []string{"gdiff", "-reverse", "-label", "old", "-label", "new", "x", "y"},
+			want: "--- new\n+++ old\n@@ -1,3 +1,2 @@\n a\n-c\n-d\n+b\n",
+		},
+		"Git": {
+			args: []string{"gdiff", "-reverse", "-git", "x", "y"},
+			want: "diff --git a/y b/x\n--- a/y\n+++ b/x\n@@ -1,3 +1,2 @@\n a\n-c\n-d\n+b\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "1")
+			var w, wErr bytes.Buffer
+			code, err := run(test.args, nil, &w, &wErr)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if code != 1 {
+				t.Errorf("run() code = %d, want 1", code)
+			}
+			if got := w.String(); got != test.want {
+				t.Errorf("run() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRunShowTrailingWhitespace(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("one\ntwo  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	var w, wErr bytes.Buffer
+	code, err := run([]string{"gdiff", "-show-trailing-ws", "-label", "a", "-label", "b", a, b}, nil, &w, &wErr)
+	if err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if code != 1 {
+		t.Errorf("run() code = %d, want 1", code)
+	}
+	want := "--- a\n+++ b\n@@ -1,2 +1,2 @@\n one\n-two\n+two··\n"
+	if got := w.String(); got != want {
+		t.Errorf("run() = %q, want %q", got, want)
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a1": "a\nb\n",
+		"a2": "a\nc\n",
+		"b1": "x\n",
+		"b2": "x\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	tests := map[string]struct {
+		manifest string
+		args     []string
+		wantCode int
+		wantErr  bool
+		want     string
+		wantWErr string
+	}{
+		"Differs": {
+			manifest: "a1\ta2\n\nb1\tb2\n",
+			wantCode: 1,
+			want:     "diff --git a/a1 b/a2\n--- a/a1\n+++ b/a2\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+		},
+		"Equal": {
+			manifest: "b1\tb2\n",
+			wantCode: 0,
+		},
+		"MissingFileContinues": {
+			manifest: "missing\tb2\na1\ta2\n",
+			wantCode: 2,
+			wantErr:  true,
+			want:     "diff --git a/a1 b/a2\n--- a/a1\n+++ b/a2\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+			wantWErr: "open missing: no such file or directory\n",
+		},
+		"FailFast": {
+			manifest: "missing\tb2\na1\ta2\n",
+			args:     []string{"-fail-fast"},
+			wantCode: 2,
+			wantErr:  true,
+		},
+		"Malformed": {
+			manifest: "a1\ta2\na1 a2\n",
+			wantCode: 2,
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "1")
+			manifest := filepath.Join(t.TempDir(), "manifest.txt")
+			if err := os.WriteFile(manifest, []byte(test.manifest), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			args := append([]string{"gdiff", "-git", "-batch", manifest}, test.args...)
+
+			var w, wErr bytes.Buffer
+			code, err := run(args, nil, &w, &wErr)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("run() error = %v, want error %t", err, test.wantErr)
+			}
+			if code != test.wantCode {
+				t.Errorf("run() code = %d, want %d", code, test.wantCode)
+			}
+			if got := w.String(); got != test.want {
+				t.Errorf("run() = %q, want %q", got, test.want)
+			}
+			if got := wErr.String(); got != test.wantWErr {
+				t.Errorf("run() stderr = %q, want %q", got, test.wantWErr)
+			}
+		})
+	}
+}
+
+func TestRunBinary(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"bin1": "a\x00b\n",
+		"bin2": "a\x00c\n",
+		"text": "a\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	tests := map[string]struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		"Differ": {
+			args:     []string{"bin1", "bin2"},
+			wantCode: 1,
+			want:     "Binary files bin1 and bin2 differ\n",
+		},
+		"OneBinary": {
+			args:     []string{"text", "bin1"},
+			wantCode: 1,
+			want:     "Binary files text and bin1 differ\n",
+		},
+		"Equal": {
+			args:     []string{"bin1", "bin1"},
+			wantCode: 0,
+		},
+		"Labels": {
+			args:     []string{"-label", "x", "-label", "y", "bin1", "bin2"},
+			wantCode: 1,
+			want:     "Binary files x and y differ\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "1")
+			var w, wErr bytes.Buffer
+			code, err := run(append([]string{"gdiff"}, test.args...), nil, &w, &wErr)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if code != test.wantCode {
+				t.Errorf("run() code = %d, want %d", code, test.wantCode)
+			}
+			if got := w.String(); got != test.want {
+				t.Errorf("run() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRunPorcelain(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		want    string
+		wantErr string
+	}{
+		"FileNotFound": {
+			args:    []string{"gdiff", "-porcelain", "testdata/nonexistent.txt", "testdata/empty.txt"},
+			wantErr: `error: file-not-found path="testdata/nonexistent.txt"`,
+		},
+		"InvalidArgument": {
+			args:    []string{"gdiff", "-porcelain", "-tabsize", "0", "testdata/empty.txt", "testdata/empty.txt"},
+			wantErr: `error: invalid-argument msg="invalid argument: tabsize 0"`,
+		},
+		"Usage": {
+			args:    []string{"gdiff", "-porcelain", "testdata/empty.txt"},
+			wantErr: `error: usage msg="usage error: expected 2 files, got 1"`,
+		},
+		"HumanByDefault": {
+			args:    []string{"gdiff", "testdata/nonexistent.txt", "testdata/empty.txt"},
+			wantErr: "open testdata/nonexistent.txt: no such file or directory",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var w, wErr bytes.Buffer
+			code, err := run(test.args, nil, &w, &wErr)
+			if code != 2 {
+				t.Errorf("run() code = %d, want 2", code)
+			}
+			if err == nil {
+				t.Fatalf("run() expected error, got nil")
+			}
+			if err.Error() != test.wantErr {
+				t.Errorf("run() error = %q, want %q", err.Error(), test.wantErr)
+			}
+		})
+	}
+}
+
+func TestBlobs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("line1\nline2\nline3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "file.txt")
+	git("commit", "-q", "-m", "first")
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("line1\nmodified\nline3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("commit", "-q", "-a", "-m", "second")
+	t.Chdir(dir)
+	t.Setenv("NO_COLOR", "1")
+
+	var buf bytes.Buffer
+	hasDiff, err := blobs(&buf, "HEAD~1:file.txt", "HEAD:file.txt", options{context: 3, contextBefore: -1, contextAfter: -1})
+	if err != nil {
+		t.Fatalf("blobs() unexpected error: %v", err)
+	}
+	if !hasDiff {
+		t.Error("blobs() hasDiff = false, want true")
+	}
+	want := `--- HEAD~1:file.txt
++++ HEAD:file.txt
+@@ -1,3 +1,3 @@
+ line1
+-line2
++modified
+ line3
+`
+	if got := buf.String(); got != want {
+		t.Errorf("blobs() mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	_, err = blobs(&buf, "HEAD:missing.txt", "HEAD:file.txt", options{context: 3, contextBefore: -1, contextAfter: -1})
+	if err == nil {
+		t.Error("blobs() expected error for missing blob, got nil")
+	}
+
+	t.Setenv("PATH", "")
+	_, err = blobs(&buf, "HEAD~1:file.txt", "HEAD:file.txt", options{context: 3, contextBefore: -1, contextAfter: -1})
+	if err == nil || err.Error() != "git-blob: git not found in PATH" {
+		t.Errorf("blobs() error = %v, want git not found", err)
+	}
+}
+
+func TestRunStdin(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"Labels": {
+			args: []string{"gdiff", "-label", "expected", "-label", "actual", "-", "testdata/multi_line_b.txt"},
+			want: `--- expected
++++ actual
+@@ -1,3 +1,3 @@
+ line1
+-line2
++modified
+ line3
+`,
+		},
+		"WithoutLabels": {
+			args: []string{"gdiff", "-", "testdata/multi_line_b.txt"},
+			want: "--- -\n" +
+				"+++ " + testFileHeader(t, "testdata/multi_line_b.txt", "testdata/multi_line_b.txt") + "\n" +
+				`@@ -1,3 +1,3 @@
+ line1
+-line2
++modified
+ line3
+`,
+		},
+		"OneLabel": {
+			args: []string{"gdiff", "-label", "expected", "testdata/multi_line_b.txt", "-"},
+			want: `--- expected
++++ -
+@@ -1,3 +1,3 @@
+ line1
+-modified
++line2
+ line3
+`,
+		},
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			in := bytes.NewBufferString("line1\nline2\nline3\n")
+			var w, wErr bytes.Buffer
+			code, err := run(test.args, in, &w, &wErr)
+			if err != nil {
+				t.Fatalf("run() unexpected error: %v", err)
+			}
+			if code != 1 {
+				t.Errorf("run() code = %d, want 1", code)
+			}
+			if got := w.String(); got != test.want {
+				t.Errorf("run() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRunStdinTwice(t *testing.T) {
+	in := bytes.NewBufferString("line1\nline2\n")
+	var w, wErr bytes.Buffer
+	code, err := run([]string{"gdiff", "-", "-"}, in, &w, &wErr)
+	if err != nil {
+		t.Fatalf("run() unexpected error: %v", err)
+	}
+	if code != 0 {
+		t.Errorf("run() code = %d, want 0", code)
+	}
+	if got := w.String(); got != "" {
+		t.Errorf("run() = %q, want no output", got)
+	}
+}
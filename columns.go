@@ -0,0 +1,36 @@
+package diff
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WithIgnoreLeadingColumns compares lines ignoring their first n runes, like a volatile record ID
+// at the start of fixed-width records. Columns are counted in runes, not bytes, and a trailing
+// newline is not a column, so the rest of a line shorter than n columns is empty. The edits
+// still hold the original lines. It panics if n is negative.
+func WithIgnoreLeadingColumns(n int) LinesOption {
+	if n < 0 {
+		panic("diff: negative number of columns")
+	}
+	return func(conf *linesConfig) {
+		conf.normalize = append(conf.normalize, func(s string) string {
+			return dropColumns(s, n)
+		})
+	}
+}
+
+// dropColumns returns s without its first n runes, keeping a trailing newline.
+func dropColumns(s string, n int) string {
+	content, hasNewline := strings.CutSuffix(s, "\n")
+	var i int
+	for ; n > 0 && i < len(content); n-- {
+		_, size := utf8.DecodeRuneInString(content[i:])
+		i += size
+	}
+	content = content[i:]
+	if hasNewline {
+		return content + "\n"
+	}
+	return content
+}
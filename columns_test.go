@@ -0,0 +1,62 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWithIgnoreLeadingColumns(t *testing.T) {
+	tests := map[string]struct {
+		a, b    []string
+		columns int
+		want    []diff.Edit
+	}{
+		"IgnoresLeadingColumns": {
+			a:       []string{"0001 alice\n", "0002 bob\n"},
+			b:       []string{"1001 alice\n", "1002 carol\n"},
+			columns: 5,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "0001 alice\n", NewLine: "1001 alice\n"},
+				{Op: diff.Del, OldLine: "0002 bob\n"},
+				{Op: diff.Ins, NewLine: "1002 carol\n"},
+			},
+		},
+		"CountsRunes": {
+			a:       []string{"äö x\n"},
+			b:       []string{"ab x\n"},
+			columns: 2,
+			want:    []diff.Edit{{Op: diff.Eq, OldLine: "äö x\n", NewLine: "ab x\n"}},
+		},
+		"ShorterLinesAreEmpty": {
+			a:       []string{"ab\n", "abc", "abcd\n"},
+			b:       []string{"xyz\n", "x", "xyze\n"},
+			columns: 3,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "ab\n", NewLine: "xyz\n"},
+				{Op: diff.Eq, OldLine: "abc", NewLine: "x"},
+				{Op: diff.Del, OldLine: "abcd\n"},
+				{Op: diff.Ins, NewLine: "xyze\n"},
+			},
+		},
+		"Zero": {
+			a:       []string{"1 a\n"},
+			b:       []string{"2 a\n"},
+			columns: 0,
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "1 a\n"},
+				{Op: diff.Ins, NewLine: "2 a\n"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines(test.a, test.b, diff.WithIgnoreLeadingColumns(test.columns))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Lines() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
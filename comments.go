@@ -0,0 +1,118 @@
+package diff
+
+import "strings"
+
+// CommentSyntax describes the comments of a language for [WithIgnoreComments].
+type CommentSyntax struct {
+	Line       string // prefix of line comments like "//" or "#", empty for none
+	BlockStart string // start of block comments like "/*", empty for none
+	BlockEnd   string // end of block comments like "*/"
+}
+
+// WithIgnoreComments compares lines ignoring comments of the given syntax, so editing the text of
+// a comment is not a change. Only whole-line comments, lines starting with the line comment prefix
+// after white space, are ignored unless block comment delimiters are given, as a prefix within a
+// line might be part of a string. Block comments are ignored wherever they start, including those
+// spanning several lines. Lines that only consist of comments and white space are absent when
+// matching, so adding or removing a comment line is not a change either. Such lines of both
+// sequences between the same matched lines are paired in order as Eq edits; the remaining ones
+// are left out of the edits, so the edits then no longer hold every line. The edits still hold
+// the original lines. Comment delimiters within string literals are not recognized. It panics if
+// only one of BlockStart and BlockEnd is set.
+func WithIgnoreComments(syntax CommentSyntax) LinesOption {
+	if (syntax.BlockStart == "") != (syntax.BlockEnd == "") {
+		panic("diff: block comment needs start and end")
+	}
+	return func(conf *linesConfig) {
+		conf.comments = &syntax
+	}
+}
+
+// stripComments returns the lines with their comments of the given syntax removed and whether
+// each line only consists of comments and white space. Such lines become empty, keeping a
+// trailing '\n'.
+func stripComments(lines []string, syntax CommentSyntax) (keys []string, commentOnly []bool) {
+	keys = make([]string, len(lines))
+	commentOnly = make([]bool, len(lines))
+	inBlock := false
+	for i, line := range lines {
+		content, newline := strings.CutSuffix(line, "\n")
+		comment := inBlock // whether the line has a comment
+		var code strings.Builder
+		for {
+			if inBlock {
+				end := strings.Index(content, syntax.BlockEnd)
+				if end < 0 {
+					break
+				}
+				content = content[end+len(syntax.BlockEnd):]
+				inBlock = false
+			} else if start := strings.Index(content, syntax.BlockStart); syntax.BlockStart != "" && start >= 0 {
+				code.WriteString(content[:start])
+				content = content[start+len(syntax.BlockStart):]
+				inBlock, comment = true, true
+			} else {
+				code.WriteString(content)
+				break
+			}
+		}
+
+		key := code.String()
+		trimmed := strings.TrimSpace(key)
+		if syntax.Line != "" && strings.HasPrefix(trimmed, syntax.Line) || comment && trimmed == "" {
+			key = ""
+			commentOnly[i] = true
+		}
+		if newline {
+			key += "\n"
+		}
+		keys[i] = key
+	}
+	return keys, commentOnly
+}
+
+// withoutComments returns the keys and lines that do not only consist of comments as reported by
+// [stripComments] together with their indices.
+func withoutComments(keys, lines []string, commentOnly []bool) (codeKeys, code []string, indices []int) {
+	for i, line := range lines {
+		if !commentOnly[i] {
+			codeKeys = append(codeKeys, keys[i])
+			code = append(code, line)
+			indices = append(indices, i)
+		}
+	}
+	return codeKeys, code, indices
+}
+
+// weaveComments inserts the lines of oldLines and newLines left out by [withoutComments] into the
+// edits of the remaining lines, whose indices are oldCode and newCode. Left out lines of both
+// sides that precede the next code line of their side are paired in order as Eq edits. The
+// unpaired ones are dropped once the code line following them is.
+func weaveComments(edits []Edit, oldLines, newLines []string, oldCode, newCode []int) []Edit {
+	woven := make([]Edit, 0, len(edits))
+	var i, j, x, y int // next left out line and next code line of the old and new lines
+	weave := func() {
+		oldEnd, newEnd := len(oldLines), len(newLines)
+		if x < len(oldCode) {
+			oldEnd = oldCode[x]
+		}
+		if y < len(newCode) {
+			newEnd = newCode[y]
+		}
+		for ; i < oldEnd && j < newEnd; i, j = i+1, j+1 {
+			woven = append(woven, Edit{Op: Eq, OldLine: oldLines[i], NewLine: newLines[j]})
+		}
+	}
+	for _, e := range edits {
+		weave()
+		woven = append(woven, e)
+		if e.Op != Ins {
+			i, x = oldCode[x]+1, x+1
+		}
+		if e.Op != Del {
+			j, y = newCode[y]+1, y+1
+		}
+	}
+	weave()
+	return woven
+}
@@ -0,0 +1,119 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestLinesIgnoreComments(t *testing.T) {
+	goSyntax := diff.CommentSyntax{Line: "//", BlockStart: "/*", BlockEnd: "*/"}
+
+	tests := map[string]struct {
+		old, new []string
+		syntax   diff.CommentSyntax
+		want     []diff.Edit
+	}{
+		"LineComment": {
+			old:    []string{"# old comment\n", "x = 1\n"},
+			new:    []string{"  # new comment\n", "x = 1\n"},
+			syntax: diff.CommentSyntax{Line: "#"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "# old comment\n", NewLine: "  # new comment\n"},
+				{Op: diff.Eq, OldLine: "x = 1\n", NewLine: "x = 1\n"},
+			},
+		},
+		"TrailingLineCommentIsCompared": {
+			old:    []string{"x = 1 # old\n"},
+			new:    []string{"x = 1 # new\n"},
+			syntax: diff.CommentSyntax{Line: "#"},
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "x = 1 # old\n"},
+				{Op: diff.Ins, NewLine: "x = 1 # new\n"},
+			},
+		},
+		"AddedCommentLine": {
+			old:    []string{"x := 1\n", "y := 2\n"},
+			new:    []string{"x := 1\n", "// note\n", "y := 2\n"},
+			syntax: goSyntax,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "x := 1\n", NewLine: "x := 1\n"},
+				{Op: diff.Eq, OldLine: "y := 2\n", NewLine: "y := 2\n"},
+			},
+		},
+		"RemovedBlockComment": {
+			old:    []string{"/* a\n", "\n", "   b */\n", "x := 1\n"},
+			new:    []string{"x := 1\n"},
+			syntax: goSyntax,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "x := 1\n", NewLine: "x := 1\n"},
+			},
+		},
+		"CommentLinesArePaired": {
+			old:    []string{"// a\n", "x := 1\n"},
+			new:    []string{"y := 2\n", "// b\n", "// c\n", "x := 1\n"},
+			syntax: goSyntax,
+			want: []diff.Edit{
+				{Op: diff.Ins, NewLine: "y := 2\n"},
+				{Op: diff.Eq, OldLine: "// a\n", NewLine: "// b\n"},
+				{Op: diff.Eq, OldLine: "x := 1\n", NewLine: "x := 1\n"},
+			},
+		},
+		"BlankLinesAreCompared": {
+			old:    []string{"x := 1\n"},
+			new:    []string{"\n", "x := 1\n"},
+			syntax: goSyntax,
+			want: []diff.Edit{
+				{Op: diff.Ins, NewLine: "\n"},
+				{Op: diff.Eq, OldLine: "x := 1\n", NewLine: "x := 1\n"},
+			},
+		},
+		"BlockCommentWithinLine": {
+			old:    []string{"x := 1 /* old */ + 2\n"},
+			new:    []string{"x := 1 /* new */ + 2\n"},
+			syntax: goSyntax,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "x := 1 /* old */ + 2\n", NewLine: "x := 1 /* new */ + 2\n"},
+			},
+		},
+		"MultiLineBlockComment": {
+			old:    []string{"/* old\n", "   text */\n", "x := 1\n"},
+			new:    []string{"/* new\n", "   words */\n", "x := 1\n"},
+			syntax: goSyntax,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "/* old\n", NewLine: "/* new\n"},
+				{Op: diff.Eq, OldLine: "   text */\n", NewLine: "   words */\n"},
+				{Op: diff.Eq, OldLine: "x := 1\n", NewLine: "x := 1\n"},
+			},
+		},
+		"CodeChange": {
+			old:    []string{"// comment\n", "x := 1\n"},
+			new:    []string{"// comment\n", "x := 2\n"},
+			syntax: goSyntax,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "// comment\n", NewLine: "// comment\n"},
+				{Op: diff.Del, OldLine: "x := 1\n"},
+				{Op: diff.Ins, NewLine: "x := 2\n"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines(test.old, test.new, diff.WithIgnoreComments(test.syntax))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Lines() = %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("UnbalancedBlockPanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("WithIgnoreComments() did not panic")
+			}
+		}()
+		diff.WithIgnoreComments(diff.CommentSyntax{BlockStart: "/*"})
+	})
+}
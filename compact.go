@@ -0,0 +1,174 @@
+package diff
+
+// compact moves the runs of changed lines in ops to their canonical position as done by git's
+// xdl_change_compact. A run that can slide is moved as late as possible unless it can line up
+// with a run of changes in the other sequence, in which case it is moved to the latest such
+// position. The number of deleted and inserted lines stays the same. oldKeys and newKeys are the
+// compared forms of the lines of each sequence.
+func compact(ops []OpType, oldKeys, newKeys []string) []OpType {
+	if len(ops) == 0 {
+		return ops
+	}
+	oldSide := side{keys: oldKeys, changed: make([]bool, len(oldKeys))}
+	newSide := side{keys: newKeys, changed: make([]bool, len(newKeys))}
+	var x, y int
+	for _, op := range ops {
+		switch op {
+		case Eq:
+			x++
+			y++
+		case Del:
+			oldSide.changed[x] = true
+			x++
+		case Ins:
+			newSide.changed[y] = true
+			y++
+		}
+	}
+	compactSide(oldSide, newSide)
+	compactSide(newSide, oldSide)
+
+	result := ops[:0]
+	x, y = 0, 0
+	for x < len(oldKeys) || y < len(newKeys) {
+		switch {
+		case x < len(oldKeys) && oldSide.changed[x]:
+			result = append(result, Del)
+			x++
+		case y < len(newKeys) && newSide.changed[y]:
+			result = append(result, Ins)
+			y++
+		default:
+			result = append(result, Eq)
+			x++
+			y++
+		}
+	}
+	return result
+}
+
+// side is one of the sequences being compacted.
+type side struct {
+	keys    []string
+	changed []bool // changed[i] reports whether line i is deleted or inserted
+}
+
+// group is a possibly empty run [start, end) of changed lines of a side bounded by unchanged
+// lines or the ends of the sequence. Every unchanged line separates two groups, so the groups of
+// both sides correspond one to one.
+type group struct {
+	start, end int
+}
+
+// compactSide slides the groups of s, keeping track of the corresponding groups of other.
+func compactSide(s, other side) {
+	g := s.first()
+	og := other.first()
+	for {
+		if g.end != g.start {
+			var earliestEnd, endMatchingOther int
+			for {
+				size := g.end - g.start
+				endMatchingOther = -1
+
+				// slide up as far as possible, merging with preceding groups
+				for s.slideUp(&g) {
+					other.previous(&og)
+				}
+				earliestEnd = g.end
+				if og.end > og.start {
+					endMatchingOther = g.end
+				}
+
+				// slide down as far as possible, merging with following groups
+				for s.slideDown(&g) {
+					other.next(&og)
+					if og.end > og.start {
+						endMatchingOther = g.end
+					}
+				}
+				if g.end-g.start == size {
+					break
+				}
+			}
+
+			// prefer lining up with a group of changes in the other side
+			if g.end != earliestEnd && endMatchingOther != -1 {
+				for og.end == og.start {
+					s.slideUp(&g)
+					other.previous(&og)
+				}
+			}
+		}
+		if !s.next(&g) {
+			return
+		}
+		other.next(&og)
+	}
+}
+
+func (s side) first() group {
+	g := group{}
+	for g.end < len(s.changed) && s.changed[g.end] {
+		g.end++
+	}
+	return g
+}
+
+// next moves g to the group following it. It returns false if g is the last group.
+func (s side) next(g *group) bool {
+	if g.end == len(s.changed) {
+		return false
+	}
+	g.start = g.end + 1
+	g.end = g.start
+	for g.end < len(s.changed) && s.changed[g.end] {
+		g.end++
+	}
+	return true
+}
+
+// previous moves g to the group preceding it. It returns false if g is the first group.
+func (s side) previous(g *group) bool {
+	if g.start == 0 {
+		return false
+	}
+	g.end = g.start - 1
+	g.start = g.end
+	for g.start > 0 && s.changed[g.start-1] {
+		g.start--
+	}
+	return true
+}
+
+// slideDown moves the non-empty group g down by one line if its first line equals the line
+// following it, merging it with the next group if they touch. It returns false if g cannot move.
+func (s side) slideDown(g *group) bool {
+	if g.end >= len(s.changed) || s.keys[g.start] != s.keys[g.end] {
+		return false
+	}
+	s.changed[g.start] = false
+	s.changed[g.end] = true
+	g.start++
+	g.end++
+	for g.end < len(s.changed) && s.changed[g.end] {
+		g.end++
+	}
+	return true
+}
+
+// slideUp moves the non-empty group g up by one line if its last line equals the line preceding
+// it, merging it with the previous group if they touch. It returns false if g cannot move.
+func (s side) slideUp(g *group) bool {
+	if g.start == 0 || s.keys[g.start-1] != s.keys[g.end-1] {
+		return false
+	}
+	g.start--
+	g.end--
+	s.changed[g.start] = true
+	s.changed[g.end] = false
+	for g.start > 0 && s.changed[g.start-1] {
+		g.start--
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteContext writes the edits to w in context format as written by GNU diff -c, for tools
+// that do not understand unified format. The edits are grouped into hunks with the given number
+// of context lines like [Write]. Each hunk starts with a line of asterisks followed by the lines
+// of the old sequence under a "*** start,end ****" header and the lines of the new sequence under
+// a "--- start,end ----" header. A run of changes deleting and inserting lines is marked by "!" on
+// both sides, otherwise deleted lines are marked by "-" and inserted lines by "+". A side without
+// changes in a hunk only has its header. Like [Write], it does not write the file header lines.
+// It panics if context is negative.
+func WriteContext(w io.Writer, edits []Edit, context int, opts ...ContextOption) error {
+	if context < 0 {
+		panic("diff: negative context")
+	}
+	conf := contextConfig{separator: "***************"}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	hunks, _ := buildHunks(edits, context, context, false)
+	bw := bufio.NewWriter(w)
+	for _, h := range hunks {
+		if err := writeContextHunk(bw, edits[h.start:h.end], h, conf.separator); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ContextOption configures [WriteContext].
+type ContextOption func(*contextConfig)
+
+type contextConfig struct {
+	separator string // line starting each hunk
+}
+
+// WithHunkSeparator sets the line starting each hunk in place of the 15 asterisks written by GNU
+// diff, for legacy tools expecting a separator of a different width. It panics if separator is
+// empty or contains a newline.
+func WithHunkSeparator(separator string) ContextOption {
+	if separator == "" || strings.Contains(separator, "\n") {
+		panic("diff: hunk separator empty or multi-line")
+	}
+	return func(conf *contextConfig) {
+		conf.separator = separator
+	}
+}
+
+// writeContextHunk writes the hunk h holding the edits in context format, starting with the
+// separator line.
+func writeContextHunk(w *bufio.Writer, edits []Edit, h hunk, separator string) error {
+	// a run of changes with both deletions and insertions is marked as changed
+	changed := make([]bool, len(edits))
+	for start := 0; start < len(edits); {
+		if edits[start].Op == Eq {
+			start++
+			continue
+		}
+		end := start
+		var del, ins bool
+		for ; end < len(edits) && edits[end].Op != Eq; end++ {
+			del = del || edits[end].Op == Del
+			ins = ins || edits[end].Op == Ins
+		}
+		for i := start; i < end; i++ {
+			changed[i] = del && ins
+		}
+		start = end
+	}
+	ins, del := countChanges(edits)
+
+	if _, err := fmt.Fprintf(w, "%s\n*** %s ****\n", separator, lineRange(h.startOld, h.countOld)); err != nil {
+		return err
+	}
+	if del > 0 {
+		for i, e := range edits {
+			if e.Op == Ins {
+				continue
+			}
+			if err := writeMarkedLine(w, contextMarker(e.Op, changed[i]), e.OldLine); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := fmt.Fprintf(w, "--- %s ----\n", lineRange(h.startNew, h.countNew)); err != nil {
+		return err
+	}
+	if ins > 0 {
+		for i, e := range edits {
+			if e.Op == Del {
+				continue
+			}
+			if err := writeMarkedLine(w, contextMarker(e.Op, changed[i]), e.NewLine); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lineRange formats the range of count lines starting at start as GNU diff does in all formats
+// but unified: "start,end", or only the line if there is at most one. An empty range starts at
+// the line preceding it like in [hunk].
+func lineRange(start, count int) string {
+	if count <= 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, start+count-1)
+}
+
+// contextMarker returns the marker of a line in context format.
+func contextMarker(op OpType, changed bool) string {
+	switch {
+	case op == Eq:
+		return "  "
+	case changed:
+		return "! "
+	case op == Del:
+		return "- "
+	default:
+		return "+ "
+	}
+}
+
+// writeMarkedLine writes the line behind its marker, followed by a no newline marker if it is
+// the last line of a sequence without a trailing newline.
+func writeMarkedLine(w *bufio.Writer, marker, line string) error {
+	if _, err := w.WriteString(marker); err != nil {
+		return err
+	}
+	if _, err := w.WriteString(line); err != nil {
+		return err
+	}
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		if _, err := w.WriteString("\n\\ No newline at end of file\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
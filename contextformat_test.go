@@ -0,0 +1,103 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteContext(t *testing.T) {
+	ten := []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n", "g\n", "h\n", "i\n", "j\n"}
+
+	// expected output as written by GNU diff -c without the file header lines
+	tests := map[string]struct {
+		old, new []string
+		context  int
+		want     string
+	}{
+		"Equal": {
+			old:     []string{"a\n"},
+			new:     []string{"a\n"},
+			context: 3,
+		},
+		"ChangedAndInserted": {
+			old:     ten,
+			new:     []string{"a\n", "b\n", "X\n", "d\n", "e\n", "f\n", "g\n", "h\n", "Y\n", "Z\n", "i\n", "j\n"},
+			context: 3,
+			want: "***************\n*** 1,10 ****\n" +
+				"  a\n  b\n! c\n  d\n  e\n  f\n  g\n  h\n  i\n  j\n" +
+				"--- 1,12 ----\n" +
+				"  a\n  b\n! X\n  d\n  e\n  f\n  g\n  h\n+ Y\n+ Z\n  i\n  j\n",
+		},
+		"OnlyDeletions": {
+			old:     []string{"a\n", "b\n", "c\n"},
+			new:     []string{"a\n", "c\n"},
+			context: 3,
+			want:    "***************\n*** 1,3 ****\n  a\n- b\n  c\n--- 1,2 ----\n",
+		},
+		"OnlyInsertions": {
+			new:     []string{"a\n", "b\n", "c\n"},
+			context: 3,
+			want:    "***************\n*** 0 ****\n--- 1,3 ----\n+ a\n+ b\n+ c\n",
+		},
+		"NoContext": {
+			old:     ten,
+			new:     []string{"a\n", "b\n", "X\n", "d\n", "e\n", "f\n", "g\n", "h\n", "Y\n", "Z\n", "i\n", "j\n"},
+			context: 0,
+			want: "***************\n*** 3 ****\n! c\n--- 3 ----\n! X\n" +
+				"***************\n*** 8 ****\n--- 9,10 ----\n+ Y\n+ Z\n",
+		},
+		"NoNewlineAtEnd": {
+			old:     []string{"a\n", "b"},
+			new:     []string{"a\n", "c"},
+			context: 3,
+			want: "***************\n*** 1,2 ****\n  a\n! b\n\\ No newline at end of file\n" +
+				"--- 1,2 ----\n  a\n! c\n\\ No newline at end of file\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := diff.WriteContext(&sb, diff.Lines(test.old, test.new), test.context); err != nil {
+				t.Fatalf("WriteContext() error: %v", err)
+			}
+			if got := sb.String(); got != test.want {
+				t.Errorf("WriteContext() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteContextHunkSeparator(t *testing.T) {
+	edits := diff.Lines([]string{"a\n", "b\n", "c\n"}, []string{"x\n", "b\n", "y\n"})
+
+	tests := map[string]struct {
+		opts []diff.ContextOption
+		want string
+	}{
+		"Default": {
+			// GNU diff -c separates hunks by 15 asterisks
+			want: "***************\n*** 1 ****\n! a\n--- 1 ----\n! x\n" +
+				"***************\n*** 3 ****\n! c\n--- 3 ----\n! y\n",
+		},
+		"Configured": {
+			opts: []diff.ContextOption{diff.WithHunkSeparator("*****")},
+			want: "*****\n*** 1 ****\n! a\n--- 1 ----\n! x\n" +
+				"*****\n*** 3 ****\n! c\n--- 3 ----\n! y\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := diff.WriteContext(&sb, edits, 0, test.opts...); err != nil {
+				t.Fatalf("WriteContext() error: %v", err)
+			}
+			if got := sb.String(); got != test.want {
+				t.Errorf("WriteContext() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package diff
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the edits to w as CSV for analysis in spreadsheets and data tools. It writes a
+// header row followed by one row per edit with the columns op, old_line_no, new_line_no,
+// old_content and new_content. The op is "eq", "del" or "ins" like in [WriteJSONL]. Line numbers
+// start at 1 and, like the content, are empty for the sequence the op does not use. The content
+// is written as is, including any trailing newline, and quoted as needed.
+func WriteCSV(w io.Writer, edits []Edit) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"op", "old_line_no", "new_line_no", "old_content", "new_content"}); err != nil {
+		return err
+	}
+	var oldLine, newLine int
+	for _, e := range edits {
+		var record []string
+		switch e.Op {
+		case Eq:
+			oldLine++
+			newLine++
+			record = []string{"eq", strconv.Itoa(oldLine), strconv.Itoa(newLine), e.OldLine, e.NewLine}
+		case Del:
+			oldLine++
+			record = []string{"del", strconv.Itoa(oldLine), "", e.OldLine, ""}
+		case Ins:
+			newLine++
+			record = []string{"ins", "", strconv.Itoa(newLine), "", e.NewLine}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,74 @@
+package diff_test
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteCSV(t *testing.T) {
+	tests := map[string]struct {
+		edits []diff.Edit
+		want  string
+	}{
+		"Empty": {
+			want: "op,old_line_no,new_line_no,old_content,new_content\n",
+		},
+		"AllOps": {
+			edits: diff.Lines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"}),
+			want: "op,old_line_no,new_line_no,old_content,new_content\n" +
+				"eq,1,1,a,a\n" +
+				"del,2,,b,\n" +
+				"ins,,2,,x\n" +
+				"eq,3,3,c,c\n" +
+				"ins,,4,,d\n",
+		},
+		"Quoting": {
+			edits: []diff.Edit{
+				{Op: diff.Del, OldLine: "a,b\n"},
+				{Op: diff.Ins, NewLine: `say "hi"`},
+			},
+			want: "op,old_line_no,new_line_no,old_content,new_content\n" +
+				"del,1,,\"a,b\n\",\n" +
+				"ins,,1,,\"say \"\"hi\"\"\"\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := diff.WriteCSV(&sb, test.edits); err != nil {
+				t.Fatalf("WriteCSV() error: %v", err)
+			}
+			if got := sb.String(); got != test.want {
+				t.Errorf("WriteCSV() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	edits := diff.Lines([]string{"a,1\n", "\"b\"\n", "c"}, []string{"a,1\n", "x\n", "c"})
+
+	var sb strings.Builder
+	if err := diff.WriteCSV(&sb, edits); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll() error: %v", err)
+	}
+	if len(records) != len(edits)+1 {
+		t.Fatalf("WriteCSV() wrote %d records, want %d", len(records), len(edits)+1)
+	}
+	for i, e := range edits {
+		got := []string{records[i+1][3], records[i+1][4]}
+		want := []string{e.OldLine, e.NewLine}
+		if !slices.Equal(got, want) {
+			t.Errorf("WriteCSV() record %d content = %q, want %q", i+1, got, want)
+		}
+	}
+}
@@ -9,9 +9,14 @@ package diff
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // OpType represents the type of edit operation.
@@ -43,67 +48,208 @@ func (op OpType) String() string {
 // '\n' delimiter. A line without a trailing '\n' represents the last line of a sequence
 // that has no final newline.
 type Edit struct {
-	Op      OpType
-	OldLine string // line from the old sequence (for Del and Eq)
-	NewLine string // line from the new sequence (for Ins and Eq)
+	Op      OpType `json:"op"`
+	OldLine string `json:"oldLine"` // line from the old sequence (for Del and Eq)
+	NewLine string `json:"newLine"` // line from the new sequence (for Ins and Eq)
 }
 
+// linesConfig holds the configuration of [Lines].
+type linesConfig struct {
+	fuzzyThreshold float64
+	// normalize transforms lines before they are compared, in order.
+	normalize []func(string) string
+	comments  *CommentSyntax    // comments to ignore, nil for none
+	isJunk    func(string) bool // lines that do not anchor the alignment, nil for none
+	timeout   time.Duration     // bound on the search, 0 for none
+	timedOut  *bool             // set to whether the timeout fired, nil to not report it
+	// blockStart and blockEnd delimit blocks of lines compared ignoring their order, nil for none.
+	blockStart, blockEnd func(string) bool
+	fieldSep             string                          // separator of the fields of a line
+	fieldEq              func(col int, a, b string) bool // equality of fields, nil to compare lines
+	lineEq               func(a, b string) bool          // equality of lines, nil to use ==
+}
+
+// LinesOption configures how [Lines] computes the edit script.
+type LinesOption func(*linesConfig)
+
 // Lines computes the shortest edit script to transform oldLines into newLines.
 // It returns a slice of [Edit] operations that, when applied in order, convert oldLines
 // to newLines.
-func Lines(oldLines, newLines []string) []Edit {
-	n := len(oldLines)
-	m := len(newLines)
-	maxD := n + m
-	if maxD == 0 {
+//
+// Where a run of changes can be placed at several positions, like deleting one of several equal
+// adjacent lines, it is moved to the latest possible position as done by git. This keeps diffs of
+// similar inputs stable and tends to group changes together.
+func Lines(oldLines, newLines []string, opts ...LinesOption) []Edit {
+	conf := &linesConfig{}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	oldKeys, newKeys := oldLines, newLines
+	allOld, allNew := oldLines, newLines
+	var oldCode, newCode []int // indices of the lines that are not only comments
+	if conf.comments != nil {
+		var oldComments, newComments []bool
+		oldKeys, oldComments = stripComments(oldKeys, *conf.comments)
+		newKeys, newComments = stripComments(newKeys, *conf.comments)
+		oldKeys, oldLines, oldCode = withoutComments(oldKeys, oldLines, oldComments)
+		newKeys, newLines, newCode = withoutComments(newKeys, newLines, newComments)
+	}
+	if len(conf.normalize) > 0 {
+		oldKeys = normalizeAll(oldKeys, conf.normalize)
+		newKeys = normalizeAll(newKeys, conf.normalize)
+	}
+	if conf.blockStart != nil {
+		oldKeys, oldLines = sortBlocks(oldKeys, oldLines, conf.blockStart, conf.blockEnd)
+		newKeys, newLines = sortBlocks(newKeys, newLines, conf.blockStart, conf.blockEnd)
+	}
+	eq := func(x, y int) bool {
+		return oldKeys[x] == newKeys[y]
+	}
+	if conf.fieldEq != nil {
+		eq = fieldsEqual(oldKeys, newKeys, conf.fieldSep, conf.fieldEq)
+	}
+	if conf.lineEq != nil {
+		eq = func(x, y int) bool {
+			return conf.lineEq(oldKeys[x], newKeys[y])
+		}
+	}
+	var ops []OpType
+	if conf.isJunk != nil {
+		ops = junkOps(oldKeys, newKeys, conf.isJunk, eq, conf.timeout, conf.timedOut)
+	} else if conf.timeout > 0 {
+		ops = timedEditOps(len(oldKeys), len(newKeys), eq, conf.timeout, conf.timedOut)
+	} else {
+		ops = editOps(len(oldKeys), len(newKeys), eq)
+	}
+	ops = compact(ops, oldKeys, newKeys)
+	edits := toEdits(ops, oldLines, newLines)
+	if conf.comments != nil {
+		edits = weaveComments(edits, allOld, allNew, oldCode, newCode)
+	}
+	if conf.fuzzyThreshold > 0 {
+		edits = pairSimilar(edits, conf.fuzzyThreshold)
+	}
+	return edits
+}
+
+// LinesFunc computes the shortest edit script to transform oldLines into newLines like [Lines]
+// using eq to compare lines instead of ==, like [strings.EqualFold] to ignore case. eq is called
+// with the lines as compared, after any option transformed them, and takes precedence over
+// [WithFieldEqual]. The edits still hold the original lines, so an Eq edit can hold lines that
+// differ.
+//
+// eq must be an equivalence relation. Runs of changes are only moved to their canonical
+// position, as described in [Lines], past lines that are identical.
+func LinesFunc(oldLines, newLines []string, eq func(a, b string) bool, opts ...LinesOption) []Edit {
+	return Lines(oldLines, newLines, append(slices.Clip(opts), func(conf *linesConfig) {
+		conf.lineEq = eq
+	})...)
+}
+
+// toEdits turns ops into edits holding the lines of oldLines and newLines they apply to.
+func toEdits(ops []OpType, oldLines, newLines []string) []Edit {
+	if len(ops) == 0 {
+		return nil
+	}
+	edits := make([]Edit, 0, len(ops))
+	var x, y int
+	for _, op := range ops {
+		switch op {
+		case Eq:
+			edits = append(edits, Edit{Op: Eq, OldLine: oldLines[x], NewLine: newLines[y]})
+			x++
+			y++
+		case Del:
+			edits = append(edits, Edit{Op: Del, OldLine: oldLines[x]})
+			x++
+		case Ins:
+			edits = append(edits, Edit{Op: Ins, NewLine: newLines[y]})
+			y++
+		}
+	}
+	return edits
+}
+
+// normalizeAll applies the transforms in order to each of the lines.
+func normalizeAll(lines []string, transforms []func(string) string) []string {
+	keys := make([]string, len(lines))
+	for i, line := range lines {
+		for _, transform := range transforms {
+			line = transform(line)
+		}
+		keys[i] = line
+	}
+	return keys
+}
+
+// editOps computes the shortest edit script to transform a sequence of length n into one of
+// length m, where eq reports whether element x of the first equals element y of the second. It
+// returns one operation per element in order: Eq consumes an element of both sequences, Del one
+// of the first and Ins one of the second.
+func editOps(n, m int, eq func(x, y int) bool) []OpType {
+	if n+m == 0 {
 		return nil
 	}
-	var edits []Edit
-	trace := shortestEdit(oldLines, newLines)
+	return backtrack(n, m, -m, n, shortestEdit(n, m, -m, n, time.Time{}, eq, nil))
+}
+
+// backtrack reconstructs the operations of the shortest edit script from the trace computed by
+// [shortestEdit] for sequences of length n and m searched within the diagonals lo through hi.
+func backtrack(n, m, lo, hi int, trace [][]int) []OpType {
+	maxD := n + m
+	// Each of the D changes and (n+m-D)/2 equal elements is one operation. Knowing the length
+	// upfront, the operations are written back to front instead of appended and reversed.
+	ops := make([]OpType, (n+m+len(trace)-1)/2)
+	pos := len(ops)
 	x, y := n, m
 	for d := len(trace) - 1; d >= 0; d-- {
 		v := trace[d]
 		k := x - y
-		i := k + maxD
+		i := k + maxD - traceOffset(d, maxD)
 		var op OpType
 		var prevK int
 		var prevX, prevY int
-		if k == -d || (k != d && v[i-1] < v[i+1]) {
+		if k == -d || k == lo || (k != d && k != hi && v[i-1] < v[i+1]) {
 			prevK = k + 1 // down i.e. insert
 			op = Ins
 		} else {
 			prevK = k - 1 // right i.e. delete
 			op = Del
 		}
-		prevX = v[prevK+maxD]
+		prevX = v[prevK-k+i]
 		prevY = prevX - prevK
 
 		for x > prevX && y > prevY { // advance on snake i.e. diagonal
-			edits = append(edits, Edit{Op: Eq, OldLine: oldLines[x-1], NewLine: newLines[y-1]})
+			pos--
+			ops[pos] = Eq
 			x--
 			y--
 		}
 
 		if d > 0 {
-			if op == Ins {
-				edits = append(edits, Edit{Op: Ins, NewLine: newLines[y-1]})
-			} else {
-				edits = append(edits, Edit{Op: Del, OldLine: oldLines[x-1]})
-			}
+			pos--
+			ops[pos] = op
 		}
 		x, y = prevX, prevY
 	}
-
-	slices.Reverse(edits)
-	return edits
+	return ops
 }
 
 // shortestEdit computes the trace of furthest reaching D-paths for transforming
-// a into b. Each element in the returned slice represents the V array state
-// before each iteration d, which is used to reconstruct the edit script.
-func shortestEdit(a, b []string) [][]int {
-	n := len(a)
-	m := len(b)
+// a sequence of length n into one of length m, where eq reports whether element x of the first
+// equals element y of the second. Each element in the returned slice represents the V array
+// state before each iteration d, which is used to reconstruct the edit script. Only the
+// diagonals -d-1 through d+1 read while reconstructing are kept, so the V array index i of
+// iteration d is at i-traceOffset(d, n+m) in trace[d].
+//
+// The search is limited to the diagonals k = x-y from lo through hi, which must include 0 and
+// n-m and lie within -m through n, the diagonals of the edit graph. Paths only move onto a
+// diagonal at the border of the band from within the band.
+//
+// If deadline is not zero, the search gives up and returns nil once it has passed. The deadline
+// is checked every deadlineInterval iterations to keep the cost of reading the clock low. If
+// explored is not nil, it is called with each diagonal k the search explores.
+func shortestEdit(n, m, lo, hi int, deadline time.Time, eq func(x, y int) bool, explored func(k int)) [][]int {
 	maxD := n + m
 	var trace [][]int
 	if maxD == 0 {
@@ -112,20 +258,23 @@ func shortestEdit(a, b []string) [][]int {
 	v := make([]int, 2*maxD+1)
 
 	for d := range maxD + 1 {
-		trace = append(trace, slices.Clone(v))
-		for k := -d; k <= d; k = k + 2 {
-			if k > n || k < -m { // skip out of bounds diagonals
-				continue
+		if !deadline.IsZero() && d%deadlineInterval == 0 && time.Now().After(deadline) {
+			return nil
+		}
+		trace = append(trace, slices.Clone(v[traceOffset(d, maxD):min(maxD+d+2, len(v))]))
+		for k := max(-d, lo+(lo+d)&1); k <= min(d, hi); k = k + 2 { // k has the parity of d
+			if explored != nil {
+				explored(k)
 			}
 			i := k + maxD
 			var x int
-			if k == -d || (k != d && v[i-1] < v[i+1]) {
+			if k == -d || k == lo || (k != d && k != hi && v[i-1] < v[i+1]) {
 				x = v[i+1] // down i.e. insert
 			} else {
 				x = v[i-1] + 1 // right i.e. delete
 			}
 			y := x - k
-			for x < n && y < m && a[x] == b[y] { // advance on snake i.e. diagonal
+			for x < n && y < m && eq(x, y) { // advance on snake i.e. diagonal
 				x++
 				y++
 			}
@@ -138,10 +287,36 @@ func shortestEdit(a, b []string) [][]int {
 	return trace
 }
 
+// deadlineInterval is the number of iterations of [shortestEdit] between checks of its deadline.
+const deadlineInterval = 16
+
+// traceOffset returns the index into the V array of the first element kept in the trace of
+// iteration d.
+func traceOffset(d, maxD int) int {
+	return max(maxD-d-1, 0)
+}
+
 type config struct {
-	context int
-	gutter  bool
-	color   bool
+	context  int
+	before   int // context lines before a change, -1 to use context
+	after    int // context lines after a change, -1 to use context
+	extra    int // context lines added before and after a change
+	gutter   bool
+	color    bool
+	separate bool
+	lineBase int
+	noWSOnly bool     // suppress hunks only changing white space
+	ctxMark  rune     // marker of unchanged lines
+	base     []string // common ancestor annotating hunks, nil for none
+	flush    bool     // flush after each hunk
+	delColor string   // escape sequence starting deleted lines
+	insColor string   // escape sequence starting inserted lines
+	headers  bool     // only write hunk headers
+	numbers  bool     // write old and new line numbers in unified format
+	trailing bool     // show trailing white space of changed lines in unified format
+	wrap     int      // width at which to wrap lines in unified format, 0 for none
+	// header formats hunk headers
+	header func(oldStart, oldCount, newStart, newCount int) string
 }
 
 // Option configures how [Write] formats its output.
@@ -158,6 +333,166 @@ func WithContext(lines int) Option {
 	}
 }
 
+// WithContextBefore sets the number of unchanged lines to show before each change, overriding
+// the value set by [WithContext]. It panics if lines is negative.
+func WithContextBefore(lines int) Option {
+	if lines < 0 {
+		panic("diff: negative context")
+	}
+	return func(conf *config) {
+		conf.before = lines
+	}
+}
+
+// WithContextAfter sets the number of unchanged lines to show after each change, overriding
+// the value set by [WithContext]. It panics if lines is negative.
+func WithContextAfter(lines int) Option {
+	if lines < 0 {
+		panic("diff: negative context")
+	}
+	return func(conf *config) {
+		conf.after = lines
+	}
+}
+
+// WithExtraContext adds lines to the unchanged lines shown before and after each change as set by
+// [WithContext], [WithContextBefore] and [WithContextAfter], like for a patch that should still
+// apply to a target that drifted from the old sequence using [ApplyFuzzy]. It only changes the
+// output: the edits, and so which lines are matched, stay the same. It panics if lines is
+// negative.
+func WithExtraContext(lines int) Option {
+	if lines < 0 {
+		panic("diff: negative context")
+	}
+	return func(conf *config) {
+		conf.extra = lines
+	}
+}
+
+// WithSeparateHunks disables merging of hunks whose context overlaps. Every run of changes is
+// written as its own hunk with its own header, even if fewer than twice the context lines
+// separate it from the next. The equal lines between two close changes are split among their
+// hunks, so no line belongs to two hunks and the output remains a valid patch.
+func WithSeparateHunks() Option {
+	return func(conf *config) {
+		conf.separate = true
+	}
+}
+
+// WithLineBase sets the number of the first line in hunk headers. It panics if base is neither 0
+// nor 1. The default is 1 as used by GNU diff. With base 0 the start of a non-empty range is the
+// 0-indexed position of its first line. The start of an empty range is the number of lines
+// preceding it in either base, which is its 0-indexed insertion position.
+func WithLineBase(base int) Option {
+	if base != 0 && base != 1 {
+		panic("diff: line base must be 0 or 1")
+	}
+	return func(conf *config) {
+		conf.lineBase = base
+	}
+}
+
+// WithSuppressWhitespaceOnlyHunks omits hunks whose changes only change the amount of white
+// space, like a hunk reindenting code. A hunk is omitted if it deletes as many lines as it inserts
+// and each deleted line equals the corresponding inserted line when ignoring changes in the
+// amount of white space like GNU diff -b. Hunks with any other change are written in full. In
+// gutter format, the lines of omitted hunks are counted as collapsed identical lines.
+func WithSuppressWhitespaceOnlyHunks() Option {
+	return func(conf *config) {
+		conf.noWSOnly = true
+	}
+}
+
+// WithContextMarker sets the marker written in front of unchanged lines instead of a space.
+// Insertions and deletions keep their + and - markers. It panics if marker is not a printable
+// character, which keeps the output aligned.
+func WithContextMarker(marker rune) Option {
+	if !unicode.IsPrint(marker) {
+		panic("diff: context marker is not printable")
+	}
+	return func(conf *config) {
+		conf.ctxMark = marker
+	}
+}
+
+// WithBase annotates each hunk with the lines of base, the common ancestor of the old and new
+// sequence, that correspond to it. The base lines are written after the hunk header, each
+// prefixed with '|' like the base section of diff3. Base is aligned to the old sequence using
+// [Lines]. The base lines of a hunk reach from the first base line following the base lines
+// matched to old lines before the hunk, up to the last base line preceding those matched to old
+// lines after it. So base lines not matching any old line at the edges of a hunk, like lines the
+// old sequence deleted, belong to the hunk. Annotations are only written in unified format.
+func WithBase(base []string) Option {
+	return func(conf *config) {
+		conf.base = base
+	}
+}
+
+// WithFlushHunks writes each hunk to the writer as soon as it is complete instead of buffering
+// the output until [Write] returns, so a consumer like a live UI sees progress. The output is
+// unchanged. Flushing after every hunk issues more and smaller writes, which lowers throughput
+// for diffs with many small hunks.
+func WithFlushHunks() Option {
+	return func(conf *config) {
+		conf.flush = true
+	}
+}
+
+// WithHunkHeader formats the hunk headers using header instead of the unified format
+// "@@ -1,3 +1,4 @@". It is called with the ranges of the hunk as in a unified hunk header, with
+// starts in the configured line base, and returns the header without a trailing newline. Hunk
+// headers are only written in unified format.
+func WithHunkHeader(header func(oldStart, oldCount, newStart, newCount int) string) Option {
+	return func(conf *config) {
+		conf.header = header
+	}
+}
+
+// WithHeadersOnly only writes the hunk headers, giving an overview of where the changes are in
+// large diffs. The hunks are grouped as usual. Since only the unified format has hunk headers, it
+// takes precedence over [WithGutter].
+func WithHeadersOnly() Option {
+	return func(conf *config) {
+		conf.headers = true
+	}
+}
+
+// WithShowTrailingWhitespace makes trailing white space of deleted and inserted lines visible in
+// unified format, so a change in trailing white space alone can be seen. Trailing spaces are
+// written as "·" and tabs as "→" like in gutter format. Equal lines are written as is.
+func WithShowTrailingWhitespace() Option {
+	return func(conf *config) {
+		conf.trailing = true
+	}
+}
+
+// WithLineNumbers writes the line numbers of the old and new sequence in two aligned columns in
+// front of the marker of each line in unified format, like a code review gutter. The column of the
+// sequence a line is not part of is blank, so insertions have no old and deletions no new line
+// number. The numbers use the configured line base. [WithGutter] takes precedence, as the gutter
+// format has line numbers of its own.
+func WithLineNumbers() Option {
+	return func(conf *config) {
+		conf.numbers = true
+	}
+}
+
+// WithWrapWidth wraps lines in unified format so that no row is wider than width runes, for
+// rendering diffs in fixed-width reports like emails or printouts. Unlike truncating, all of a
+// line is kept across as many rows as needed. Continuation rows are indented by blanks up to where
+// the line starts, so the marker column and any line numbers of [WithLineNumbers] stay clear.
+// Width is counted in runes, a tab being one rune. Each row holds at least one rune of the line,
+// even if width leaves no room after the marker and line numbers. The output is meant for reading and is no
+// longer a valid patch. It panics if width is less than 1. [WithGutter] takes precedence.
+func WithWrapWidth(width int) Option {
+	if width < 1 {
+		panic("diff: wrap width less than 1")
+	}
+	return func(conf *config) {
+		conf.wrap = width
+	}
+}
+
 // WithGutter enables gutter format: each line is prefixed with a line number from the old
 // sequence, an operation indicator, and a │ separator. Whitespace in changed lines is made
 // visible (spaces as ·, tabs as →, trailing newlines as ↵). Runs of identical lines beyond
@@ -171,33 +506,117 @@ func WithGutter() Option {
 // WithColor enables ANSI color output: deletions are red (\033[31m) and insertions are
 // green (\033[32m). The caller is responsible for terminal detection and NO_COLOR handling.
 func WithColor() Option {
+	return WithColors("\033[31m", "\033[32m")
+}
+
+// WithColors enables color output like [WithColor] using the given escape sequences to start
+// deleted and inserted lines, like "\033[38;5;160m" for a color of the 256-color palette. Colored
+// lines are ended by \033[0m. The caller is responsible for choosing sequences the terminal
+// supports.
+func WithColors(del, ins string) Option {
 	return func(conf *config) {
 		conf.color = true
+		conf.delColor = del
+		conf.insColor = ins
 	}
 }
 
 // Write writes the edits to w. By default it produces unified diff output with hunk headers
-// and 3 lines of context. Use [WithGutter] and [WithContext] to configure the output.
+// and 3 lines of context. Use [WithGutter], [WithContext], [WithContextBefore] and
+// [WithContextAfter] to configure the output.
+//
+// In unified format, the first column of each line of a hunk is always its marker followed by the
+// line as is, unless [WithLineNumbers] is used. Lines that start with "+", "-", " " or "@"
+// themselves, like those of a diff of two patches, are thus not escaped. A reader stays
+// unambiguous by taking the first column as the marker and by counting the lines of each hunk
+// against the counts in its header instead of looking for the next "@@".
 func Write(w io.Writer, edits []Edit, opts ...Option) error {
-	conf := &config{context: 3}
-	for _, opt := range opts {
-		opt(conf)
+	conf := newConfig(opts)
+	if conf.headers {
+		conf.gutter = false
 	}
-	hunks, maxOldLine := buildHunks(edits, conf.context)
+	hunks, maxOldLine := conf.hunks(edits)
 	var lw int
-	if conf.gutter {
+	if conf.gutter || conf.numbers {
+		maxLine := maxOldLine
+		if !conf.gutter {
+			// the new sequence has every line that is not deleted
+			ins, del := countChanges(edits)
+			maxLine = max(maxOldLine, maxOldLine-del+ins) + conf.lineBase - 1
+		}
 		lw = 1
-		for v := maxOldLine; v > 9; v /= 10 {
+		for v := maxLine; v > 9; v /= 10 {
 			lw++
 		}
 	}
+	var bases [][]string
+	if conf.base != nil && !conf.gutter {
+		bases = baseLines(conf.base, edits, hunks)
+	}
 	bw := bufio.NewWriter(w)
-	if err := writeHunks(bw, edits, hunks, conf, lw); err != nil {
+	if err := writeHunks(bw, edits, hunks, bases, conf, lw); err != nil {
 		return err
 	}
 	return bw.Flush()
 }
 
+// newConfig returns the configuration of [Write] with opts applied to its defaults.
+func newConfig(opts []Option) *config {
+	conf := &config{context: 3, before: -1, after: -1, lineBase: 1, ctxMark: ' ', header: hunkHeader}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	return conf
+}
+
+// hunks groups edits into the hunks written with conf like [buildHunks], leaving out the hunks
+// suppressed by [WithSuppressWhitespaceOnlyHunks].
+func (conf *config) hunks(edits []Edit) (hunks []hunk, maxOldLine int) {
+	before, after := conf.context, conf.context
+	if conf.before >= 0 {
+		before = conf.before
+	}
+	if conf.after >= 0 {
+		after = conf.after
+	}
+	hunks, maxOldLine = buildHunks(edits, before+conf.extra, after+conf.extra, conf.separate)
+	if conf.noWSOnly {
+		hunks = slices.DeleteFunc(hunks, func(h hunk) bool {
+			return whitespaceOnly(edits[h.start:h.end])
+		})
+	}
+	return hunks, maxOldLine
+}
+
+// LinesUnified computes the edits transforming oldLines into newLines like [Lines] and also
+// returns their unified diff rendering with the given number of context lines as written by
+// [Write]. The rendering is empty if the sequences are equal.
+func LinesUnified(oldLines, newLines []string, context int) ([]Edit, string) {
+	edits := Lines(oldLines, newLines)
+	var sb strings.Builder
+	_ = Write(&sb, edits, WithContext(context)) // writing to a strings.Builder cannot fail
+	return edits, sb.String()
+}
+
+// UnifiedPatch returns the unified diff transforming a into b with the given number of context
+// lines, headed by "--- oldName" and "+++ newName" lines. Unlike the output of gdiff, the header
+// carries no modification times, so the patch only depends on its arguments, as needed for golden
+// files. It returns nil if a and b are equal.
+func UnifiedPatch(oldName, newName string, a, b []string, context int) []byte {
+	return unifiedPatch(oldName, newName, Lines(a, b), context)
+}
+
+// unifiedPatch returns the unified diff of edits like [UnifiedPatch].
+func unifiedPatch(oldName, newName string, edits []Edit, context int) []byte {
+	if !slices.ContainsFunc(edits, func(e Edit) bool { return e.Op != Eq }) {
+		return nil
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+	_ = Write(&buf, edits, WithContext(context)) // writing to a bytes.Buffer cannot fail
+	return buf.Bytes()
+}
+
 // hunk represents a group of contiguous changes with surrounding context lines.
 type hunk struct {
 	startOld, startNew int // 1-indexed start line numbers
@@ -205,179 +624,136 @@ type hunk struct {
 	start, end         int // index range into edits [start, end)
 }
 
-// buildHunks groups edits into hunks, merging hunks separated by fewer than 2*context equal
-// lines. It also returns maxOldLine, the highest line number in the old sequence (for gutter
-// line-number width).
-func buildHunks(edits []Edit, context int) (hunks []hunk, maxOldLine int) {
-	var lineOld int // current line number in the old sequence
-	var lineNew int // current line number in the new sequence
-
-	hunkStart := -1  // index into edits where the current hunk starts (0-indexed, -1 if no active hunk)
-	var hunkEnd int  // index into edits where the current hunk ends (0-indexed, inclusive)
-	var startOld int // start line in the old sequence for the current hunk (1-indexed)
-	var startNew int // start line in the new sequence for the current hunk (1-indexed)
-	var countOld int // number of old lines in the current hunk
-	var countNew int // number of new lines in the current hunk
-	var eqCount int  // consecutive equal lines since the last change
-
-	for i, edit := range edits {
-		switch edit.Op {
-		case Eq:
-			lineNew++
-			lineOld++
+// buildHunks groups edits into hunks with up to before equal lines of context preceding and up
+// to after equal lines following each change. Hunks separated by no more than before+after
+// equal lines are merged unless separate is set, in which case every run of changes gets its own
+// hunk and the equal lines between close changes are split among their hunks. It also returns
+// maxOldLine, the highest line number in the old sequence (for gutter line-number width).
+func buildHunks(edits []Edit, before, after int, separate bool) (hunks []hunk, maxOldLine int) {
+	for _, e := range edits {
+		if e.Op != Ins {
 			maxOldLine++
+		}
+	}
 
-			if hunkStart >= 0 {
-				hunkEnd = i
-
-				// set start line for the side that did not initiate the hunk
-				if context > 0 {
-					if startOld == 0 {
-						startOld = lineOld
-					} else if startNew == 0 {
-						startNew = lineNew
-					}
-				} else {
-					if startOld == 0 {
-						startOld = lineOld - 1
-					} else if startNew == 0 {
-						startNew = lineNew - 1
-					}
-				}
-
-				if eqCount+1 > 2*context { // hunk end
-					// adjust for the extra eq we counted to wait for a possibly merged hunk
-					if context > 0 && eqCount > context {
-						adjust := eqCount - context
-						countOld -= adjust
-						countNew -= adjust
-						hunkEnd -= adjust
-					}
-
-					hunks = append(hunks, hunk{
-						startOld: startOld,
-						countOld: countOld,
-						startNew: startNew,
-						countNew: countNew,
-						start:    hunkStart,
-						end:      hunkEnd,
-					})
-					hunkStart = -1
-					hunkEnd = -1
-					startNew = 0
-					startOld = 0
-					eqCount = 0
-					countNew = 0
-					countOld = 0
-				} else {
-					eqCount++
-					countNew++
-					countOld++
-				}
+	var lineOld, lineNew int // lines consumed before edits[i] per side
+	var cur hunk
+	active := false
+	lastChange := -1 // index of the last change in the current hunk
+	prevEnd := 0     // end of the previous hunk
+	for i, e := range edits {
+		if e.Op != Eq {
+			gap := i - lastChange - 1
+			if active && (gap > before+after || separate && gap > 0) {
+				// split the equal lines between separate hunks so no line belongs to both,
+				// giving the following hunk up to half of them as leading context
+				prevEnd = lastChange + 1 + min(after, gap-min(before, gap/2))
+				hunks = append(hunks, closeHunk(edits, cur, prevEnd))
+				active = false
 			}
-		case Ins:
-			lineNew++
-			countNew++
-			eqCount = 0
-			hunkEnd = i
-
-			if hunkStart < 0 { // starting new hunk
-				hunkStart = max(0, i-context)
-				context := i - hunkStart
-				countOld += context
-				countNew += context
-				if context > 0 {
-					startOld = lineOld - context + 1
-				}
-				startNew = lineNew - context
-			} else { // part of an existing hunk
-				if startNew == 0 {
-					startNew = lineNew
-				}
+			if !active {
+				cur = hunk{start: max(i-before, prevEnd)}
+				// count the leading context lines back to the hunk start
+				cur.startOld = lineOld - (i - cur.start)
+				cur.startNew = lineNew - (i - cur.start)
+				active = true
 			}
-		case Del:
+			lastChange = i
+		}
+		if e.Op != Ins {
 			lineOld++
-			maxOldLine++
-			countOld++
-			eqCount = 0
-			hunkEnd = i
-
-			if hunkStart < 0 { // starting new hunk
-				hunkStart = max(0, i-context)
-				context := i - hunkStart
-				countOld += context
-				countNew += context
-				if context > 0 {
-					startNew = lineNew - context + 1
-				}
-				startOld = lineOld - context
-			} else { // part of an existing hunk
-				if startOld == 0 {
-					startOld = lineOld
-				}
-			}
 		}
+		if e.Op != Del {
+			lineNew++
+		}
+	}
+	if active {
+		hunks = append(hunks, closeHunk(edits, cur, min(lastChange+1+after, len(edits))))
 	}
+	return hunks, maxOldLine
+}
 
-	// flush remaining hunk
-	if hunkStart >= 0 {
-		if startOld == 0 {
-			startOld = lineOld
-		} else if startNew == 0 {
-			startNew = lineNew
+// closeHunk ends h at the edit index end and computes its line counts. h.startOld and h.startNew
+// hold the number of lines preceding the hunk on entry and are turned into 1-indexed start
+// lines. As in GNU diff, a side without any lines in the hunk reports the line preceding the
+// hunk as its start.
+func closeHunk(edits []Edit, h hunk, end int) hunk {
+	h.end = end
+	for _, e := range edits[h.start:h.end] {
+		if e.Op != Ins {
+			h.countOld++
 		}
-		// adjust for the extra eq we counted to wait for a possibly merged hunk
-		if context > 0 && eqCount > context {
-			adjust := eqCount - context
-			countOld -= adjust
-			countNew -= adjust
-			hunkEnd -= adjust
+		if e.Op != Del {
+			h.countNew++
 		}
-
-		hunks = append(hunks, hunk{
-			startOld: startOld,
-			countOld: countOld,
-			startNew: startNew,
-			countNew: countNew,
-			start:    hunkStart,
-			end:      hunkEnd + 1,
-		})
 	}
-	return
+	if h.countOld > 0 {
+		h.startOld++
+	}
+	if h.countNew > 0 {
+		h.startNew++
+	}
+	return h
 }
 
-func writeHunks(w *bufio.Writer, edits []Edit, hunks []hunk, conf *config, lineWidth int) error {
+// writeHunks writes the hunks of edits. If bases is not nil, it holds the base lines annotating
+// each hunk.
+func writeHunks(w *bufio.Writer, edits []Edit, hunks []hunk, bases [][]string, conf *config, lineWidth int) error {
 	for i, h := range hunks {
 		if !conf.gutter {
-			if err := writeHunkHeader(w, h.startOld, h.countOld, h.startNew, h.countNew); err != nil {
+			startOld := rebase(h.startOld, h.countOld, conf.lineBase)
+			startNew := rebase(h.startNew, h.countNew, conf.lineBase)
+			if _, err := w.WriteString(conf.header(startOld, h.countOld, startNew, h.countNew) + "\n"); err != nil {
 				return err
 			}
-		} else if i != 0 {
+			if bases != nil && !conf.headers {
+				if err := writeBase(w, bases[i]); err != nil {
+					return err
+				}
+			}
+		} else if i != 0 && h.start > hunks[i-1].end {
 			collapsedEqs := h.start - hunks[i-1].end
 			if _, err := fmt.Fprintf(w, "%*s───┼─── %d identical line(s) ───\n", lineWidth, "", collapsedEqs); err != nil {
 				return err
 			}
 		}
 
-		oldLine := h.startOld
-		for j := h.start; j < h.end; j++ {
+		oldLine, newLine := h.startOld, h.startNew
+		for j := h.start; j < h.end && !conf.headers; j++ {
 			e := edits[j]
-			if err := writeEdit(w, e, oldLine, conf, lineWidth); err != nil {
+			if err := writeEdit(w, e, oldLine, newLine, conf, lineWidth); err != nil {
 				return err
 			}
 			if e.Op != Ins {
 				oldLine++
 			}
+			if e.Op != Del {
+				newLine++
+			}
+		}
+		if conf.flush {
+			if err := w.Flush(); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
-// writeHunkHeader writes a hunk header in unified diff format.
+// rebase converts the 1-indexed start of a hunk range to the given line base. Empty ranges
+// (count 0) are left alone as their start already denotes the position between lines, which is
+// the number of lines preceding the range.
+func rebase(start, count, lineBase int) int {
+	if count == 0 {
+		return start
+	}
+	return start + lineBase - 1
+}
+
+// hunkHeader returns a hunk header in unified diff format.
 // When count is 1, it is omitted (e.g., @@ -2 +2 @@ instead of @@ -2,1 +2,1 @@).
-func writeHunkHeader(w io.Writer, oldStart, oldCount, newStart, newCount int) error {
-	_, err := fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
-	return err
+func hunkHeader(oldStart, oldCount, newStart, newCount int) string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
 }
 
 func hunkRange(start, count int) string {
@@ -387,7 +763,7 @@ func hunkRange(start, count int) string {
 	return fmt.Sprintf("%d,%d", start, count)
 }
 
-func writeEdit(w *bufio.Writer, e Edit, oldLine int, conf *config, lineWidth int) error {
+func writeEdit(w *bufio.Writer, e Edit, oldLine, newLine int, conf *config, lineWidth int) error {
 	line := e.NewLine
 	if e.Op == Del {
 		line = e.OldLine
@@ -395,9 +771,9 @@ func writeEdit(w *bufio.Writer, e Edit, oldLine int, conf *config, lineWidth int
 	if conf.color && e.Op != Eq {
 		var err error
 		if e.Op == Del {
-			_, err = w.WriteString("\033[31m")
+			_, err = w.WriteString(conf.delColor)
 		} else {
-			_, err = w.WriteString("\033[32m")
+			_, err = w.WriteString(conf.insColor)
 		}
 		if err != nil {
 			return err
@@ -413,7 +789,7 @@ func writeEdit(w *bufio.Writer, e Edit, oldLine int, conf *config, lineWidth int
 				return err
 			}
 		}
-		if _, err := w.WriteString(e.Op.String()); err != nil {
+		if err := writeMarker(w, e.Op, conf); err != nil {
 			return err
 		}
 		if _, err := w.WriteString(" │ "); err != nil {
@@ -424,15 +800,103 @@ func writeEdit(w *bufio.Writer, e Edit, oldLine int, conf *config, lineWidth int
 		}
 		return writeReset(w, e.Op, conf)
 	}
-	if _, err := w.WriteString(e.Op.String()); err != nil {
+	if conf.numbers {
+		if err := writeLineNumber(w, oldLine+conf.lineBase-1, e.Op != Ins, lineWidth); err != nil {
+			return err
+		}
+		if err := writeLineNumber(w, newLine+conf.lineBase-1, e.Op != Del, lineWidth); err != nil {
+			return err
+		}
+	}
+	if err := writeMarker(w, e.Op, conf); err != nil {
 		return err
 	}
+	if conf.trailing && e.Op != Eq {
+		line = showTrailingWhitespace(line)
+	}
+	if conf.wrap > 0 {
+		indent := 1
+		if conf.numbers {
+			indent += 2 * (lineWidth + 1)
+		}
+		line = wrapLine(line, conf.wrap-indent, indent)
+	}
 	if err := writeLine(w, line, false, conf); err != nil {
 		return err
 	}
 	return writeReset(w, e.Op, conf)
 }
 
+// writeLineNumber writes n right-aligned to width followed by a space, or blanks of the same
+// width if the line is not part of the sequence.
+func writeLineNumber(w *bufio.Writer, n int, show bool, width int) error {
+	if !show {
+		_, err := fmt.Fprintf(w, "%*s", width+1, "")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%*d ", width, n)
+	return err
+}
+
+// wrapLine breaks line into rows of width runes, at least one, starting each row after the first
+// on a new line indented by indent blanks. A trailing newline is kept.
+func wrapLine(line string, width, indent int) string {
+	width = max(width, 1)
+	content, hasNewline := strings.CutSuffix(line, "\n")
+	if utf8.RuneCountInString(content) <= width {
+		return line
+	}
+	var sb strings.Builder
+	var n int
+	for _, r := range content {
+		if n == width {
+			sb.WriteByte('\n')
+			sb.WriteString(strings.Repeat(" ", indent))
+			n = 0
+		}
+		sb.WriteRune(r)
+		n++
+	}
+	if hasNewline {
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// showTrailingWhitespace replaces the trailing spaces and tabs of line by "·" and "→", keeping a
+// trailing newline.
+func showTrailingWhitespace(line string) string {
+	content, hasNewline := strings.CutSuffix(line, "\n")
+	text := strings.TrimRight(content, " \t")
+	if len(text) == len(content) {
+		return line
+	}
+	var sb strings.Builder
+	sb.WriteString(text)
+	for _, r := range content[len(text):] {
+		if r == ' ' {
+			sb.WriteRune('·')
+		} else {
+			sb.WriteRune('→')
+		}
+	}
+	if hasNewline {
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// writeMarker writes the operation marker of a line, using the configured context marker for
+// equal lines.
+func writeMarker(w *bufio.Writer, op OpType, conf *config) error {
+	if op == Eq {
+		_, err := w.WriteRune(conf.ctxMark)
+		return err
+	}
+	_, err := w.WriteString(op.String())
+	return err
+}
+
 func writeReset(w *bufio.Writer, op OpType, conf *config) error {
 	if conf.color && op != Eq {
 		_, err := w.WriteString("\033[0m")
@@ -2,7 +2,10 @@ package diff_test
 
 import (
 	"bytes"
+	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/teleivo/diff"
@@ -104,6 +107,39 @@ func TestLines(t *testing.T) {
 	}
 }
 
+func TestLinesFunc(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		opts []diff.LinesOption
+		want []diff.Edit
+	}{
+		"IgnoreCase": {
+			a: []string{"Host = example.com\n", "Port = 80\n"},
+			b: []string{"host = Example.com\n", "port = 8080\n"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "Host = example.com\n", NewLine: "host = Example.com\n"},
+				{Op: diff.Del, OldLine: "Port = 80\n"},
+				{Op: diff.Ins, NewLine: "port = 8080\n"},
+			},
+		},
+		"WithOptions": {
+			a:    []string{"A  b\n"},
+			b:    []string{"a B\n"},
+			opts: []diff.LinesOption{diff.WithIgnoreSpaceChange()},
+			want: []diff.Edit{{Op: diff.Eq, OldLine: "A  b\n", NewLine: "a B\n"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.LinesFunc(test.a, test.b, strings.EqualFold, test.opts...)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("LinesFunc() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
 func TestWrite(t *testing.T) {
 	tests := map[string]struct {
 		edits       []diff.Edit
@@ -702,3 +738,882 @@ func TestWriteGutterColor(t *testing.T) {
 		t.Errorf("Write() =\n%q\nwant:\n%q", got, want)
 	}
 }
+
+func TestWriteHunkHeader(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Del, OldLine: "b\n"},
+		{Op: diff.Ins, NewLine: "c\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+	}
+	header := func(oldStart, oldCount, newStart, newCount int) string {
+		return fmt.Sprintf("### lines %d-%d => %d-%d ###", oldStart, oldStart+oldCount-1, newStart, newStart+newCount-1)
+	}
+
+	var buf bytes.Buffer
+	if err := diff.Write(&buf, edits, diff.WithHunkHeader(header)); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	want := "### lines 1-3 => 1-3 ###\n a\n-b\n+c\n d\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Write() =\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestWriteHeadersOnly(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Del, OldLine: "a\n"},
+		{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+		{Op: diff.Ins, NewLine: "e\n"},
+	}
+
+	for name, opts := range map[string][]diff.Option{
+		"Unified": {diff.WithContext(0), diff.WithHeadersOnly()},
+		"Gutter":  {diff.WithContext(0), diff.WithHeadersOnly(), diff.WithGutter()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := diff.Write(&buf, edits, opts...); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			want := "@@ -1 +0,0 @@\n@@ -4,0 +4 @@\n"
+			if got := buf.String(); got != want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteLineNumbers(t *testing.T) {
+	eq := func(s string) diff.Edit { return diff.Edit{Op: diff.Eq, OldLine: s + "\n", NewLine: s + "\n"} }
+	del := func(s string) diff.Edit { return diff.Edit{Op: diff.Del, OldLine: s + "\n"} }
+	ins := func(s string) diff.Edit { return diff.Edit{Op: diff.Ins, NewLine: s + "\n"} }
+
+	tests := map[string]struct {
+		edits []diff.Edit
+		opts  []diff.Option
+		want  string
+	}{
+		"Unified": {
+			edits: []diff.Edit{eq("a"), del("b"), ins("x"), ins("y"), eq("c")},
+			opts:  []diff.Option{diff.WithLineNumbers()},
+			want:  "@@ -1,3 +1,4 @@\n1 1  a\n2   -b\n  2 +x\n  3 +y\n3 4  c\n",
+		},
+		"WidthOfLongerSide": {
+			edits: []diff.Edit{
+				eq("a"), eq("b"), eq("c"), eq("d"), eq("e"), eq("f"), eq("g"), eq("h"),
+				ins("x"), ins("y"), eq("i"),
+			},
+			opts: []diff.Option{diff.WithLineNumbers(), diff.WithContext(1)},
+			want: "@@ -8,2 +8,4 @@\n 8  8  h\n    9 +x\n   10 +y\n 9 11  i\n",
+		},
+		"LineBase": {
+			edits: []diff.Edit{del("a"), eq("b")},
+			opts:  []diff.Option{diff.WithLineNumbers(), diff.WithLineBase(0)},
+			want:  "@@ -0,2 +0 @@\n0   -a\n1 0  b\n",
+		},
+		"GutterTakesPrecedence": {
+			edits: []diff.Edit{del("a"), ins("b")},
+			opts:  []diff.Option{diff.WithLineNumbers(), diff.WithGutter()},
+			want:  "1 - │ a↵\n  + │ b↵\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := diff.Write(&buf, test.edits, test.opts...); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWritePatchContent(t *testing.T) {
+	old := []string{"@@ -1 +1 @@\n", "-a\n", "+b\n", " c\n"}
+	new := []string{"@@ -1 +1 @@\n", "-a\n", "+x\n", " c\n"}
+
+	var buf bytes.Buffer
+	if err := diff.Write(&buf, diff.Lines(old, new), diff.WithContext(1)); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	want := "@@ -2,3 +2,3 @@\n -a\n-+b\n++x\n  c\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("Write() =\n%q\nwant:\n%q", got, want)
+	}
+
+	// the marker is the first column and the rest of the line is the content as is
+	var gotOld, gotNew []string
+	for _, line := range strings.SplitAfter(buf.String(), "\n")[1:5] {
+		marker, content := line[0], line[1:]
+		if marker != '+' {
+			gotOld = append(gotOld, content)
+		}
+		if marker != '-' {
+			gotNew = append(gotNew, content)
+		}
+	}
+	if !slices.Equal(gotOld, old[1:]) {
+		t.Errorf("old lines = %q, want %q", gotOld, old[1:])
+	}
+	if !slices.Equal(gotNew, new[1:]) {
+		t.Errorf("new lines = %q, want %q", gotNew, new[1:])
+	}
+}
+
+func TestWriteShowTrailingWhitespace(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a \n", NewLine: "a \n"},
+		{Op: diff.Del, OldLine: "b\n"},
+		{Op: diff.Ins, NewLine: "b \t \n"},
+		{Op: diff.Del, OldLine: "c d  "},
+	}
+
+	var buf bytes.Buffer
+	if err := diff.Write(&buf, edits, diff.WithShowTrailingWhitespace()); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	want := "@@ -1,3 +1,2 @@\n a \n-b\n+b·→·\n-c d··\n\\ No newline at end of file\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Write() =\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestWriteWrapWidth(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "short\n", NewLine: "short\n"},
+		{Op: diff.Del, OldLine: "abcdefghij\n"},
+		{Op: diff.Ins, NewLine: "äöüäöüä"},
+	}
+
+	tests := map[string]struct {
+		opts []diff.Option
+		want string
+	}{
+		"Unified": {
+			opts: []diff.Option{diff.WithWrapWidth(5)},
+			want: "@@ -1,2 +1,2 @@\n" +
+				" shor\n" +
+				" t\n" +
+				"-abcd\n" +
+				" efgh\n" +
+				" ij\n" +
+				"+äöüä\n" +
+				" öüä\n" +
+				"\\ No newline at end of file\n",
+		},
+		"FitsWidth": {
+			opts: []diff.Option{diff.WithWrapWidth(11)},
+			want: "@@ -1,2 +1,2 @@\n short\n-abcdefghij\n+äöüäöüä\n\\ No newline at end of file\n",
+		},
+		"LineNumbers": {
+			opts: []diff.Option{diff.WithWrapWidth(10), diff.WithLineNumbers()},
+			want: "@@ -1,2 +1,2 @@\n" +
+				"1 1  short\n" +
+				"2   -abcde\n" +
+				"     fghij\n" +
+				"  2 +äöüäö\n" +
+				"     üä\n" +
+				"\\ No newline at end of file\n",
+		},
+		"NarrowerThanPrefix": {
+			opts: []diff.Option{diff.WithWrapWidth(1)},
+			want: "@@ -1,2 +1,2 @@\n" +
+				" s\n h\n o\n r\n t\n" +
+				"-a\n b\n c\n d\n e\n f\n g\n h\n i\n j\n" +
+				"+ä\n ö\n ü\n ä\n ö\n ü\n ä\n" +
+				"\\ No newline at end of file\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := diff.Write(&buf, edits, test.opts...); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("Write() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteNoNewlineAtEndOfFile(t *testing.T) {
+	// expected output as written by GNU diff -u
+	tests := map[string]struct {
+		old, new []string
+		want     string
+	}{
+		"DeleteLastLine": {
+			old:  []string{"a\n", "b"},
+			new:  []string{"a\n"},
+			want: "@@ -1,2 +1 @@\n a\n-b\n\\ No newline at end of file\n",
+		},
+		"InsertLastLine": {
+			old:  []string{"a\n"},
+			new:  []string{"a\n", "b"},
+			want: "@@ -1 +1,2 @@\n a\n+b\n\\ No newline at end of file\n",
+		},
+		"AddNewline": {
+			old:  []string{"a\n", "b"},
+			new:  []string{"a\n", "b\n"},
+			want: "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		"RemoveNewline": {
+			old:  []string{"a\n", "b\n"},
+			new:  []string{"a\n", "b"},
+			want: "@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		"EqualLastLineInContext": {
+			old:  []string{"a\n", "b\n", "c"},
+			new:  []string{"x\n", "b\n", "c"},
+			want: "@@ -1,3 +1,3 @@\n-a\n+x\n b\n c\n\\ No newline at end of file\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := diff.Write(&buf, diff.Lines(test.old, test.new)); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("Write() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteContextLargerThanFile(t *testing.T) {
+	// expected output as written by GNU diff -U3
+	tests := map[string]struct {
+		old, new []string
+		want     string
+	}{
+		"OneLineChanged": {
+			old:  []string{"a\n"},
+			new:  []string{"b\n"},
+			want: "@@ -1 +1 @@\n-a\n+b\n",
+		},
+		"LastOfTwoChanged": {
+			old:  []string{"a\n", "b\n"},
+			new:  []string{"a\n", "c\n"},
+			want: "@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+		},
+		"FirstOfTwoChanged": {
+			old:  []string{"a\n", "b\n"},
+			new:  []string{"x\n", "b\n"},
+			want: "@@ -1,2 +1,2 @@\n-a\n+x\n b\n",
+		},
+		"OldEmpty": {
+			new:  []string{"a\n"},
+			want: "@@ -0,0 +1 @@\n+a\n",
+		},
+		"NewEmpty": {
+			old:  []string{"a\n"},
+			want: "@@ -1 +0,0 @@\n-a\n",
+		},
+		"AppendedToTwo": {
+			old:  []string{"a\n", "b\n"},
+			new:  []string{"a\n", "b\n", "c\n"},
+			want: "@@ -1,2 +1,3 @@\n a\n b\n+c\n",
+		},
+		"AppendedToOne": {
+			old:  []string{"a\n"},
+			new:  []string{"a\n", "b\n"},
+			want: "@@ -1 +1,2 @@\n a\n+b\n",
+		},
+		"MiddleOfThreeDeleted": {
+			old:  []string{"a\n", "b\n", "c\n"},
+			new:  []string{"a\n", "c\n"},
+			want: "@@ -1,3 +1,2 @@\n a\n-b\n c\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := diff.Write(&buf, diff.Lines(test.old, test.new), diff.WithContext(3)); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteColors(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Del, OldLine: "a\n"},
+		{Op: diff.Ins, NewLine: "b\n"},
+	}
+
+	var buf bytes.Buffer
+	err := diff.Write(&buf, edits, diff.WithColors("\033[38;5;160m", "\033[38;5;34m"))
+	if err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	want := "@@ -1 +1 @@\n\033[38;5;160m-a\n\033[0m\033[38;5;34m+b\n\033[0m"
+	if got := buf.String(); got != want {
+		t.Errorf("Write() =\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestWriteAsymmetricContext(t *testing.T) {
+	eq := func(s string) diff.Edit { return diff.Edit{Op: diff.Eq, OldLine: s + "\n", NewLine: s + "\n"} }
+	del := func(s string) diff.Edit { return diff.Edit{Op: diff.Del, OldLine: s + "\n"} }
+	ins := func(s string) diff.Edit { return diff.Edit{Op: diff.Ins, NewLine: s + "\n"} }
+
+	tests := map[string]struct {
+		edits []diff.Edit
+		opts  []diff.Option
+		want  string
+	}{
+		"MoreBefore": {
+			edits: []diff.Edit{eq("a"), eq("b"), eq("c"), eq("d"), del("x"), eq("e"), eq("f"), eq("g")},
+			opts:  []diff.Option{diff.WithContextBefore(2), diff.WithContextAfter(1)},
+			want:  "@@ -3,4 +3,3 @@\n c\n d\n-x\n e\n",
+		},
+		"MoreAfter": {
+			edits: []diff.Edit{eq("a"), eq("b"), eq("c"), del("x"), eq("d"), eq("e"), eq("f")},
+			opts:  []diff.Option{diff.WithContextBefore(0), diff.WithContextAfter(2)},
+			want:  "@@ -4,3 +4,2 @@\n-x\n d\n e\n",
+		},
+		"OverridesContext": {
+			edits: []diff.Edit{eq("a"), eq("b"), ins("x"), eq("c"), eq("d")},
+			opts:  []diff.Option{diff.WithContextAfter(0), diff.WithContext(1)},
+			want:  "@@ -2 +2,2 @@\n b\n+x\n",
+		},
+		"GapEqualsBeforePlusAfterMerges": {
+			edits: []diff.Edit{del("x"), eq("a"), eq("b"), eq("c"), ins("y")},
+			opts:  []diff.Option{diff.WithContextBefore(2), diff.WithContextAfter(1)},
+			want:  "@@ -1,4 +1,4 @@\n-x\n a\n b\n c\n+y\n",
+		},
+		"GapAboveBeforePlusAfterSeparates": {
+			edits: []diff.Edit{del("x"), eq("a"), eq("b"), eq("c"), eq("d"), ins("y")},
+			opts:  []diff.Option{diff.WithContextBefore(2), diff.WithContextAfter(1)},
+			want:  "@@ -1,2 +1 @@\n-x\n a\n@@ -4,2 +3,3 @@\n c\n d\n+y\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := diff.Write(&buf, test.edits, test.opts...)
+			if err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			got := buf.String()
+			if got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteExtraContext(t *testing.T) {
+	eq := func(s string) diff.Edit { return diff.Edit{Op: diff.Eq, OldLine: s + "\n", NewLine: s + "\n"} }
+	del := func(s string) diff.Edit { return diff.Edit{Op: diff.Del, OldLine: s + "\n"} }
+
+	edits := []diff.Edit{eq("a"), eq("b"), eq("c"), eq("d"), del("x"), eq("e"), eq("f"), eq("g")}
+	tests := map[string]struct {
+		opts []diff.Option
+		want string
+	}{
+		"AddsToContext": {
+			opts: []diff.Option{diff.WithContext(1), diff.WithExtraContext(2)},
+			want: "@@ -2,7 +2,6 @@\n b\n c\n d\n-x\n e\n f\n g\n",
+		},
+		"AddsToContextBeforeAndAfter": {
+			opts: []diff.Option{diff.WithContextBefore(0), diff.WithContextAfter(1), diff.WithExtraContext(1)},
+			want: "@@ -4,4 +4,3 @@\n d\n-x\n e\n f\n",
+		},
+		"None": {
+			opts: []diff.Option{diff.WithContext(1), diff.WithExtraContext(0)},
+			want: "@@ -4,3 +4,2 @@\n d\n-x\n e\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := diff.Write(&buf, edits, test.opts...)
+			if err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			got := buf.String()
+			if got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("SameChanges", func(t *testing.T) {
+		a := []string{"1\n", "2\n", "3\n", "4\n", "5\n", "6\n", "7\n"}
+		b := []string{"1\n", "2\n", "3\n", "four\n", "5\n", "6\n", "7\n"}
+		hunks := diff.Hunks(diff.Lines(a, b), 0, diff.WithExtraContext(2))
+		if len(hunks) != 1 {
+			t.Fatalf("Hunks() = %v, want 1 hunk", hunks)
+		}
+		want := []diff.Edit{eq("2"), eq("3"), del("4"), {Op: diff.Ins, NewLine: "four\n"}, eq("5"), eq("6")}
+		if !slices.Equal(hunks[0].Edits, want) {
+			t.Errorf("Hunks() edits = %q, want %q", hunks[0].Edits, want)
+		}
+		drifted := append([]string{"0\n"}, a...)
+		got, err := diff.ApplyFuzzy(drifted, hunks, 1)
+		if err != nil {
+			t.Fatalf("ApplyFuzzy() error: %v", err)
+		}
+		if want := append([]string{"0\n"}, b...); !slices.Equal(got, want) {
+			t.Errorf("ApplyFuzzy() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestLinesUnified(t *testing.T) {
+	t.Run("Changed", func(t *testing.T) {
+		oldLines := []string{"a\n", "b\n", "c\n"}
+		newLines := []string{"a\n", "x\n", "c\n"}
+
+		edits, got := diff.LinesUnified(oldLines, newLines, 1)
+
+		wantEdits := diff.Lines(oldLines, newLines)
+		if !slices.Equal(edits, wantEdits) {
+			t.Errorf("LinesUnified() edits = %v, want %v", edits, wantEdits)
+		}
+		want := "@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"
+		if got != want {
+			t.Errorf("LinesUnified() =\n%q\nwant:\n%q", got, want)
+		}
+	})
+	t.Run("Equal", func(t *testing.T) {
+		lines := []string{"a\n", "b\n"}
+
+		edits, got := diff.LinesUnified(lines, lines, 3)
+
+		if len(edits) != 2 {
+			t.Errorf("LinesUnified() returned %d edits, want 2", len(edits))
+		}
+		if got != "" {
+			t.Errorf("LinesUnified() = %q, want empty", got)
+		}
+	})
+}
+
+func TestWriteSeparateHunks(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Del, OldLine: "x\n"},
+		{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+		{Op: diff.Del, OldLine: "y\n"},
+		{Op: diff.Ins, NewLine: "z\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+	}
+
+	tests := map[string]struct {
+		opts []diff.Option
+		want string
+	}{
+		"Merged": {
+			opts: []diff.Option{diff.WithContext(1)},
+			want: "@@ -1,6 +1,5 @@\n a\n-x\n b\n c\n-y\n+z\n d\n",
+		},
+		"Separate": {
+			opts: []diff.Option{diff.WithContext(1), diff.WithSeparateHunks()},
+			want: "@@ -1,3 +1,2 @@\n a\n-x\n b\n@@ -4,3 +3,3 @@\n c\n-y\n+z\n d\n",
+		},
+		"SeparateContextStopsAtChange": {
+			opts: []diff.Option{diff.WithContext(3), diff.WithSeparateHunks()},
+			want: "@@ -1,3 +1,2 @@\n a\n-x\n b\n@@ -4,3 +3,3 @@\n c\n-y\n+z\n d\n",
+		},
+		"SeparateContextAfterOnly": {
+			opts: []diff.Option{diff.WithContextBefore(0), diff.WithContextAfter(3), diff.WithSeparateHunks()},
+			want: "@@ -2,3 +2,2 @@\n-x\n b\n c\n@@ -5,2 +4,2 @@\n-y\n+z\n d\n",
+		},
+		"SeparateGutter": {
+			opts: []diff.Option{diff.WithContext(0), diff.WithSeparateHunks(), diff.WithGutter()},
+			want: "2 - │ x↵\n" +
+				" ───┼─── 2 identical line(s) ───\n" +
+				"5 - │ y↵\n" +
+				"  + │ z↵\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := diff.Write(&buf, edits, test.opts...)
+			if err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			got := buf.String()
+			if got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteLineBase(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Del, OldLine: "b\n"},
+		{Op: diff.Ins, NewLine: "x\n"},
+		{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+		{Op: diff.Eq, OldLine: "e\n", NewLine: "e\n"},
+		{Op: diff.Ins, NewLine: "y\n"},
+	}
+
+	tests := map[string]struct {
+		base int
+		want string
+	}{
+		"OneBased": {
+			base: 1,
+			want: "@@ -2 +2 @@\n-b\n+x\n@@ -5,0 +6 @@\n+y\n",
+		},
+		"ZeroBased": {
+			base: 0,
+			want: "@@ -1 +1 @@\n-b\n+x\n@@ -5,0 +5 @@\n+y\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := diff.Write(&buf, edits, diff.WithContext(0), diff.WithLineBase(test.base))
+			if err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			got := buf.String()
+			if got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("InvalidBasePanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("WithLineBase(2) did not panic")
+			}
+		}()
+		diff.WithLineBase(2)
+	})
+}
+
+func TestWriteClampsContextAtBoundaries(t *testing.T) {
+	lines := func(s ...string) []string {
+		for i := range s {
+			s[i] += "\n"
+		}
+		return s
+	}
+	oldLines := lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10")
+
+	// expected headers were cross-checked against GNU diff -U5
+	tests := map[string]struct {
+		newLines []string
+		want     string
+	}{
+		"ChangeOnSecondLine": {
+			newLines: lines("1", "X", "3", "4", "5", "6", "7", "8", "9", "10"),
+			want:     "@@ -1,7 +1,7 @@\n 1\n-2\n+X\n 3\n 4\n 5\n 6\n 7\n",
+		},
+		"InsertAfterFirstLine": {
+			newLines: lines("1", "I", "2", "3", "4", "5", "6", "7", "8", "9", "10"),
+			want:     "@@ -1,6 +1,7 @@\n 1\n+I\n 2\n 3\n 4\n 5\n 6\n",
+		},
+		"DeleteFirstLine": {
+			newLines: lines("2", "3", "4", "5", "6", "7", "8", "9", "10"),
+			want:     "@@ -1,6 +1,5 @@\n-1\n 2\n 3\n 4\n 5\n 6\n",
+		},
+		"ChangeOnSecondToLastLine": {
+			newLines: lines("1", "2", "3", "4", "5", "6", "7", "8", "Y", "10"),
+			want:     "@@ -4,7 +4,7 @@\n 4\n 5\n 6\n 7\n 8\n-9\n+Y\n 10\n",
+		},
+		"DeleteSecondToLastLine": {
+			newLines: lines("1", "2", "3", "4", "5", "6", "7", "8", "10"),
+			want:     "@@ -4,7 +4,6 @@\n 4\n 5\n 6\n 7\n 8\n-9\n 10\n",
+		},
+		"AppendLine": {
+			newLines: lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"),
+			want:     "@@ -6,5 +6,6 @@\n 6\n 7\n 8\n 9\n 10\n+11\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := diff.Write(&buf, diff.Lines(oldLines, test.newLines), diff.WithContext(5))
+			if err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			got := buf.String()
+			if got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteSuppressWhitespaceOnlyHunks(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Del, OldLine: "func foo() {\n"},
+		{Op: diff.Ins, NewLine: "func  foo()  {  \n"},
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+		{Op: diff.Del, OldLine: "return 1\n"},
+		{Op: diff.Ins, NewLine: "return  2\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+		{Op: diff.Eq, OldLine: "e\n", NewLine: "e\n"},
+		{Op: diff.Eq, OldLine: "f\n", NewLine: "f\n"},
+		{Op: diff.Del, OldLine: "ab\n"},
+		{Op: diff.Ins, NewLine: "a b\n"},
+	}
+
+	var buf bytes.Buffer
+	err := diff.Write(&buf, edits, diff.WithContext(0), diff.WithSuppressWhitespaceOnlyHunks())
+	if err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	// the first hunk only changes the amount of white space, inserting white space between
+	// words in the last hunk is a real change
+	want := "@@ -5 +5 @@\n-return 1\n+return  2\n@@ -9 +9 @@\n-ab\n+a b\n"
+	got := buf.String()
+	if got != want {
+		t.Errorf("Write() =\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestWriteContextMarker(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "keep1\n", NewLine: "keep1\n"},
+		{Op: diff.Del, OldLine: "removed\n"},
+		{Op: diff.Ins, NewLine: "added\n"},
+		{Op: diff.Eq, OldLine: "keep2\n", NewLine: "keep2\n"},
+	}
+
+	t.Run("Unified", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := diff.Write(&buf, edits, diff.WithContext(1), diff.WithContextMarker('='))
+		if err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+		want := "@@ -1,3 +1,3 @@\n=keep1\n-removed\n+added\n=keep2\n"
+		if got := buf.String(); got != want {
+			t.Errorf("Write() =\n%q\nwant:\n%q", got, want)
+		}
+	})
+	t.Run("Gutter", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := diff.Write(&buf, edits, diff.WithContext(1), diff.WithContextMarker('='), diff.WithGutter())
+		if err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+		want := "1 = │ keep1\n" +
+			"2 - │ removed↵\n" +
+			"  + │ added↵\n" +
+			"3 = │ keep2\n"
+		if got := buf.String(); got != want {
+			t.Errorf("Write() =\n%q\nwant:\n%q", got, want)
+		}
+	})
+	t.Run("NotPrintablePanics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("WithContextMarker('\\t') did not panic")
+			}
+		}()
+		diff.WithContextMarker('\t')
+	})
+}
+
+func TestUnifiedPatch(t *testing.T) {
+	a := []string{"line1\n", "line2\n", "line3\n"}
+	b := []string{"line1\n", "modified\n", "line3\n"}
+
+	want := "--- a.txt\n+++ b.txt\n@@ -1,3 +1,3 @@\n line1\n-line2\n+modified\n line3\n"
+	for range 2 {
+		if got := diff.UnifiedPatch("a.txt", "b.txt", a, b, 3); string(got) != want {
+			t.Errorf("UnifiedPatch() =\n%q\nwant:\n%q", got, want)
+		}
+	}
+
+	if got := diff.UnifiedPatch("a.txt", "b.txt", a, a, 3); got != nil {
+		t.Errorf("UnifiedPatch() of equal sequences = %q, want nil", got)
+	}
+}
+
+func TestWriteFlushHunks(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Del, OldLine: "a\n"},
+		{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+		{Op: diff.Ins, NewLine: "e\n"},
+	}
+
+	var buffered bytes.Buffer
+	if err := diff.Write(&buffered, edits, diff.WithContext(0)); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	var flushed writeRecorder
+	if err := diff.Write(&flushed, edits, diff.WithContext(0), diff.WithFlushHunks()); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	want := []string{"@@ -1 +0,0 @@\n-a\n", "@@ -4,0 +4 @@\n+e\n"}
+	if !slices.Equal(flushed.writes, want) {
+		t.Errorf("Write() writes = %q, want %q", flushed.writes, want)
+	}
+	if got := strings.Join(flushed.writes, ""); got != buffered.String() {
+		t.Errorf("Write() with flushing = %q, want %q", got, buffered.String())
+	}
+}
+
+// writeRecorder records each write.
+type writeRecorder struct {
+	writes []string
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestWriteBase(t *testing.T) {
+	old := []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n", "g\n"}
+	tests := map[string]struct {
+		base    []string
+		new     []string
+		context int
+		want    string
+	}{
+		"Changed": {
+			base:    []string{"a\n", "B\n", "c\n", "d\n", "e\n", "f\n", "g\n"},
+			new:     []string{"a\n", "x\n", "c\n", "d\n", "e\n", "f\n", "g\n"},
+			context: 1,
+			want:    "@@ -1,3 +1,3 @@\n|a\n|B\n|c\n a\n-b\n+x\n c\n",
+		},
+		"DeletedFromBase": {
+			base:    []string{"a\n", "b\n", "c\n", "d\n", "removed\n", "e\n", "f\n", "g\n"},
+			new:     []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n", "x\n"},
+			context: 1,
+			want:    "@@ -6,2 +6,2 @@\n|f\n|g\n f\n-g\n+x\n",
+		},
+		"DeletedFromBaseAtEdge": {
+			base:    []string{"a\n", "b\n", "removed\n", "c\n", "d\n", "e\n", "f\n", "g\n"},
+			new:     []string{"a\n", "b\n", "x\n", "d\n", "e\n", "f\n", "g\n"},
+			context: 1,
+			want:    "@@ -2,3 +2,3 @@\n|b\n|removed\n|c\n|d\n b\n-c\n+x\n d\n",
+		},
+		"Insertion": {
+			base:    []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n", "g\n"},
+			new:     []string{"a\n", "b\n", "c\n", "x\n", "d\n", "e\n", "f\n", "g\n"},
+			context: 0,
+			want:    "@@ -3,0 +4 @@\n+x\n",
+		},
+		"EmptyBase": {
+			base:    []string{},
+			new:     []string{"a\n", "x\n", "c\n", "d\n", "e\n", "f\n", "g\n"},
+			context: 1,
+			want:    "@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := diff.Write(&buf, diff.Lines(old, test.new), diff.WithContext(test.context), diff.WithBase(test.base))
+			if err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLinesCanonicalPosition(t *testing.T) {
+	split := func(s string) []string {
+		lines := strings.SplitAfter(s, "\n")
+		return lines[:len(lines)-1]
+	}
+
+	// expected output was cross-checked against git diff --no-index --no-indent-heuristic
+	tests := map[string]struct {
+		old, new string
+		context  int
+		want     string
+	}{
+		"DeleteRepeatedLine": {
+			old:  "x\n}\n}\n}\ny\n",
+			new:  "x\n}\n}\ny\n",
+			want: "@@ -4 +3,0 @@\n-}\n",
+		},
+		"ReplaceFirstOfRepeatedLines": {
+			old:  "}\n}\n}\n",
+			new:  "{\n}\n}\n",
+			want: "@@ -1 +1 @@\n-}\n+{\n",
+		},
+		"ReplaceWithMoreLines": {
+			old:  "}\n}\n",
+			new:  "{\n{\n}\n",
+			want: "@@ -1 +1,2 @@\n-}\n+{\n+{\n",
+		},
+		"AppendBlock": {
+			old:     "{\n}\n\n{\n}\n",
+			new:     "{\n}\n\n{\n}\n\n{\n}\n",
+			context: 1,
+			want:    "@@ -5 +5,4 @@\n }\n+\n+{\n+}\n",
+		},
+		"BlockMove": {
+			old:     "func a() {\n\treturn 1\n}\n\nfunc b() {\n\treturn 2\n}\n\nfunc c() {\n\treturn 3\n}\n",
+			new:     "func b() {\n\treturn 2\n}\n\nfunc a() {\n\treturn 1\n}\n\nfunc c() {\n\treturn 3\n}\n",
+			context: 1,
+			want: "@@ -1,5 +1 @@\n-func a() {\n-\treturn 1\n-}\n-\n func b() {\n" +
+				"@@ -8,2 +4,6 @@\n \n+func a() {\n+\treturn 1\n+}\n+\n func c() {\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := diff.Write(&buf, diff.Lines(split(test.old), split(test.new)), diff.WithContext(test.context))
+			if err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			got := buf.String()
+			if got != test.want {
+				t.Errorf("Write() =\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func BenchmarkLinesCompletelyDifferent(b *testing.B) {
+	oldLines := make([]string, 1000)
+	newLines := make([]string, 1000)
+	for i := range oldLines {
+		oldLines[i] = "old " + strconv.Itoa(i) + "\n"
+		newLines[i] = "new " + strconv.Itoa(i) + "\n"
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		diff.Lines(oldLines, newLines)
+	}
+}
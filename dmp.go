@@ -0,0 +1,74 @@
+package diff
+
+import "strings"
+
+// DMPOp is the operation of a [DMPDiff] using the values of Google's diff-match-patch library.
+type DMPOp int
+
+const (
+	// DMPDelete indicates text deleted from the old text.
+	DMPDelete DMPOp = -1
+	// DMPEqual indicates text present in both texts.
+	DMPEqual DMPOp = 0
+	// DMPInsert indicates text inserted from the new text.
+	DMPInsert DMPOp = 1
+)
+
+// DMPDiff is a single (operation, text) tuple as used by Google's diff-match-patch library.
+type DMPDiff struct {
+	Type DMPOp
+	Text string
+}
+
+// ToDMP converts edits into the diff-match-patch representation. Consecutive edits of the same
+// operation are merged into a single [DMPDiff] whose text is the concatenation of their lines.
+// Equal lines use the text of the old line. Lines keep their trailing '\n' so the line
+// boundaries are preserved in the text; this requires every line but the last of a sequence to
+// end in '\n', as it does for lines split with [strings.SplitAfter].
+func ToDMP(edits []Edit) []DMPDiff {
+	var diffs []DMPDiff
+	for _, e := range edits {
+		var d DMPDiff
+		switch e.Op {
+		case Ins:
+			d = DMPDiff{Type: DMPInsert, Text: e.NewLine}
+		case Del:
+			d = DMPDiff{Type: DMPDelete, Text: e.OldLine}
+		case Eq:
+			d = DMPDiff{Type: DMPEqual, Text: e.OldLine}
+		}
+		if n := len(diffs); n > 0 && diffs[n-1].Type == d.Type {
+			diffs[n-1].Text += d.Text
+			continue
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
+
+// FromDMP converts diff-match-patch diffs as created by [ToDMP] back into edits. The text of each
+// diff is split into lines after each '\n'. A line not ending in '\n' can only be the last line
+// of a sequence.
+func FromDMP(diffs []DMPDiff) []Edit {
+	var edits []Edit
+	for _, d := range diffs {
+		if d.Text == "" {
+			continue
+		}
+		lines := strings.SplitAfter(d.Text, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		for _, line := range lines {
+			switch d.Type {
+			case DMPInsert:
+				edits = append(edits, Edit{Op: Ins, NewLine: line})
+			case DMPDelete:
+				edits = append(edits, Edit{Op: Del, OldLine: line})
+			case DMPEqual:
+				edits = append(edits, Edit{Op: Eq, OldLine: line, NewLine: line})
+			}
+		}
+	}
+	return edits
+}
@@ -0,0 +1,46 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestToDMP(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		{Op: diff.Del, OldLine: "c\n"},
+		{Op: diff.Del, OldLine: "d\n"},
+		{Op: diff.Ins, NewLine: "x\n"},
+		{Op: diff.Eq, OldLine: "e\n", NewLine: "e\n"},
+		{Op: diff.Del, OldLine: "f"},
+		{Op: diff.Ins, NewLine: "f\n"},
+	}
+
+	got := diff.ToDMP(edits)
+
+	want := []diff.DMPDiff{
+		{Type: diff.DMPEqual, Text: "a\nb\n"},
+		{Type: diff.DMPDelete, Text: "c\nd\n"},
+		{Type: diff.DMPInsert, Text: "x\n"},
+		{Type: diff.DMPEqual, Text: "e\n"},
+		{Type: diff.DMPDelete, Text: "f"},
+		{Type: diff.DMPInsert, Text: "f\n"},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ToDMP() =\n%q\nwant:\n%q", got, want)
+	}
+
+	roundTrip := diff.FromDMP(got)
+	if !slices.Equal(roundTrip, edits) {
+		t.Errorf("FromDMP(ToDMP()) =\n%q\nwant:\n%q", roundTrip, edits)
+	}
+}
+
+func TestToDMPEmpty(t *testing.T) {
+	if got := diff.ToDMP(nil); got != nil {
+		t.Errorf("ToDMP(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,76 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteEd writes the edits to w as an ed script as written by GNU diff -e, for tools applying
+// diffs with ed. Each run of changes becomes an a (append), c (change) or d (delete) command on
+// the lines of the old sequence, followed by the new lines and a line holding a single "." for a
+// and c. Runs of deleted and inserted lines are coalesced into a single c command. The commands
+// are written from the last to the first run, so the line numbers of a command are not shifted by
+// the commands before it. A new line consisting of a single "." is written as ".." and fixed up
+// by an s/.// command like GNU diff does. Like ed scripts, the output cannot express a missing
+// newline at the end of the new sequence.
+func WriteEd(w io.Writer, edits []Edit) error {
+	hunks, _ := buildHunks(edits, 0, 0, false)
+	bw := bufio.NewWriter(w)
+	for i := len(hunks) - 1; i >= 0; i-- {
+		h := hunks[i]
+		cmd := "c"
+		switch {
+		case h.countOld == 0:
+			cmd = "a"
+		case h.countNew == 0:
+			cmd = "d"
+		}
+		if _, err := fmt.Fprintf(bw, "%s%s\n", lineRange(h.startOld, h.countOld), cmd); err != nil {
+			return err
+		}
+		if h.countNew == 0 {
+			continue
+		}
+		if err := writeEdLines(bw, edits[h.start:h.end]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// writeEdLines writes the inserted lines of edits as the input of an a or c command terminated by
+// a single ".". A line consisting of a single "." would end the input, so it is written as "..",
+// the input is ended and the line is fixed up by s/.//. Any remaining lines are appended after it
+// by another a command.
+func writeEdLines(w *bufio.Writer, edits []Edit) error {
+	open := true // whether the input of a command is being written
+	for _, e := range edits {
+		if e.Op != Ins {
+			continue
+		}
+		if !open {
+			if _, err := w.WriteString("a\n"); err != nil {
+				return err
+			}
+			open = true
+		}
+		line := strings.TrimSuffix(e.NewLine, "\n")
+		if line == "." {
+			if _, err := w.WriteString("..\n.\ns/.//\n"); err != nil {
+				return err
+			}
+			open = false
+			continue
+		}
+		if _, err := w.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	if !open {
+		return nil
+	}
+	_, err := w.WriteString(".\n")
+	return err
+}
@@ -0,0 +1,71 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteEd(t *testing.T) {
+	// expected output as written by GNU diff -e
+	tests := map[string]struct {
+		old, new []string
+		want     string
+	}{
+		"Equal": {
+			old: []string{"a\n"},
+			new: []string{"a\n"},
+		},
+		"ChangeDeleteAppend": {
+			old:  []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n"},
+			new:  []string{"a\n", "X\n", "c\n", "e\n", "f\n", "Y\n", "Z\n"},
+			want: "6a\nY\nZ\n.\n4d\n2c\nX\n.\n",
+		},
+		"ChangeRange": {
+			old:  []string{"1\n", "2\n", "3\n", "4\n", "5\n", "6\n", "7\n"},
+			new:  []string{"1\n", "2\n", "3\n", "X\n", "Y\n", "6\n", "7\n"},
+			want: "4,5c\nX\nY\n.\n",
+		},
+		"DeleteRange": {
+			old:  []string{"1\n", "2\n", "3\n", "4\n"},
+			new:  []string{"1\n", "4\n"},
+			want: "2,3d\n",
+		},
+		"AppendToEmpty": {
+			new:  []string{"a\n"},
+			want: "0a\na\n.\n",
+		},
+		"Dot": {
+			old:  []string{"a\n"},
+			new:  []string{"a\n", ".\n", "b\n"},
+			want: "1a\n..\n.\ns/.//\na\nb\n.\n",
+		},
+		"DotLast": {
+			old:  []string{"a\n"},
+			new:  []string{"a\n", ".\n"},
+			want: "1a\n..\n.\ns/.//\n",
+		},
+		"Dots": {
+			new:  []string{".\n", ".\n"},
+			want: "0a\n..\n.\ns/.//\na\n..\n.\ns/.//\n",
+		},
+		"NoNewlineAtEnd": {
+			old:  []string{"a\n"},
+			new:  []string{"a\n", "b"},
+			want: "1a\nb\n.\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := diff.WriteEd(&sb, diff.Lines(test.old, test.new)); err != nil {
+				t.Fatalf("WriteEd() error: %v", err)
+			}
+			if got := sb.String(); got != test.want {
+				t.Errorf("WriteEd() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
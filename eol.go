@@ -0,0 +1,40 @@
+package diff
+
+import (
+	"bytes"
+	"strings"
+)
+
+// OnlyLineEndingsDiffer reports whether a and b differ but are equal when CRLF line endings are
+// replaced by LF, like a file and a copy of it converted to Windows line endings.
+func OnlyLineEndingsDiffer(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return false
+	}
+	return bytes.Equal(bytes.ReplaceAll(a, []byte("\r\n"), []byte("\n")), bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n")))
+}
+
+// CountLineEndings returns the number of LF line endings not preceded by CR and the number of
+// CRLF line endings in data.
+func CountLineEndings(data []byte) (lf, crlf int) {
+	crlf = bytes.Count(data, []byte("\r\n"))
+	return bytes.Count(data, []byte("\n")) - crlf, crlf
+}
+
+// WithStripTrailingCR compares lines ignoring a carriage return at their end, before any trailing
+// newline, like GNU diff --strip-trailing-cr. A file with LF line endings then equals a copy of it
+// with CRLF line endings, so only lines that changed otherwise are deleted and inserted. Unlike
+// GNU diff, the edits still hold the original lines including their carriage return.
+func WithStripTrailingCR() LinesOption {
+	return func(conf *linesConfig) {
+		conf.normalize = append(conf.normalize, stripTrailingCR)
+	}
+}
+
+// stripTrailingCR removes a carriage return at the end of line, before any trailing newline.
+func stripTrailingCR(line string) string {
+	if content, ok := strings.CutSuffix(line, "\r\n"); ok {
+		return content + "\n"
+	}
+	return strings.TrimSuffix(line, "\r")
+}
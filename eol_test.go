@@ -0,0 +1,79 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestOnlyLineEndingsDiffer(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"Equal":               {a: "a\nb\n", b: "a\nb\n", want: false},
+		"LFAndCRLF":           {a: "a\nb\n", b: "a\r\nb\r\n", want: true},
+		"Mixed":               {a: "a\r\nb\n", b: "a\nb\r\n", want: true},
+		"ContentDiffers":      {a: "a\nb\n", b: "a\r\nc\r\n", want: false},
+		"StrayCarriageReturn": {a: "a\rb\n", b: "ab\n", want: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := diff.OnlyLineEndingsDiffer([]byte(test.a), []byte(test.b)); got != test.want {
+				t.Errorf("OnlyLineEndingsDiffer(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCountLineEndings(t *testing.T) {
+	lf, crlf := diff.CountLineEndings([]byte("a\nb\r\nc\r\nd\re"))
+	if lf != 1 || crlf != 2 {
+		t.Errorf("CountLineEndings() = %d, %d, want 1, 2", lf, crlf)
+	}
+}
+
+func TestWithStripTrailingCR(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want []diff.Edit
+	}{
+		"LFAndCRLFOneLineChanged": {
+			a: []string{"a\n", "b\n", "c\n"},
+			b: []string{"a\r\n", "x\r\n", "c\r\n"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\r\n"},
+				{Op: diff.Del, OldLine: "b\n"},
+				{Op: diff.Ins, NewLine: "x\r\n"},
+				{Op: diff.Eq, OldLine: "c\n", NewLine: "c\r\n"},
+			},
+		},
+		"LastLineWithoutNewline": {
+			a: []string{"a\n", "b"},
+			b: []string{"a\r\n", "b\r"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\r\n"},
+				{Op: diff.Eq, OldLine: "b", NewLine: "b\r"},
+			},
+		},
+		"OnlyTrailingCR": {
+			a: []string{"a\rb\n"},
+			b: []string{"ab\n"},
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "a\rb\n"},
+				{Op: diff.Ins, NewLine: "ab\n"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines(test.a, test.b, diff.WithStripTrailingCR())
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Lines() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
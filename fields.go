@@ -0,0 +1,48 @@
+package diff
+
+import "strings"
+
+// WithFieldEqual compares lines field by field, like records of tabular data where one column is
+// case-insensitive while another is compared exactly. Lines are split into fields at each sep
+// and two lines are equal if they have the same number of fields and eq returns true for each
+// pair of fields, where col is the index of the field starting at 0. A trailing newline is not
+// part of the last field. eq is called with the lines as compared, after any other option
+// transformed them. The edits still hold the original lines.
+//
+// eq must be an equivalence relation. Runs of changes are only moved to their canonical
+// position, as described in [Lines], past lines that are identical.
+func WithFieldEqual(sep string, eq func(col int, a, b string) bool) LinesOption {
+	return func(conf *linesConfig) {
+		conf.fieldSep = sep
+		conf.fieldEq = eq
+	}
+}
+
+// fieldsEqual returns a func reporting whether oldKeys[x] equals newKeys[y] field by field as
+// described by [WithFieldEqual]. The lines are split into fields once upfront.
+func fieldsEqual(oldKeys, newKeys []string, sep string, eq func(col int, a, b string) bool) func(x, y int) bool {
+	oldFields := splitFields(oldKeys, sep)
+	newFields := splitFields(newKeys, sep)
+	return func(x, y int) bool {
+		a, b := oldFields[x], newFields[y]
+		if len(a) != len(b) {
+			return false
+		}
+		for col := range a {
+			if !eq(col, a[col], b[col]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// splitFields splits each of the lines into its fields separated by sep, dropping a trailing
+// newline.
+func splitFields(lines []string, sep string) [][]string {
+	fields := make([][]string, len(lines))
+	for i, line := range lines {
+		fields[i] = strings.Split(strings.TrimSuffix(line, "\n"), sep)
+	}
+	return fields
+}
@@ -0,0 +1,74 @@
+package diff_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWithFieldEqual(t *testing.T) {
+	// column 0 is an exact ID, column 1 a case-insensitive name
+	eq := func(col int, a, b string) bool {
+		if col == 1 {
+			return strings.EqualFold(a, b)
+		}
+		return a == b
+	}
+
+	tests := map[string]struct {
+		a, b []string
+		want []diff.Edit
+	}{
+		"CaseInsensitiveColumn": {
+			a: []string{"1,alice\n", "2,bob\n"},
+			b: []string{"1,ALICE\n", "2,Bob\n"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "1,alice\n", NewLine: "1,ALICE\n"},
+				{Op: diff.Eq, OldLine: "2,bob\n", NewLine: "2,Bob\n"},
+			},
+		},
+		"ExactColumn": {
+			a: []string{"1,alice\n", "a,bob\n"},
+			b: []string{"1,alice\n", "A,bob\n"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "1,alice\n", NewLine: "1,alice\n"},
+				{Op: diff.Del, OldLine: "a,bob\n"},
+				{Op: diff.Ins, NewLine: "A,bob\n"},
+			},
+		},
+		"OtherColumnsExact": {
+			a: []string{"1,alice,Berlin"},
+			b: []string{"1,Alice,berlin"},
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "1,alice,Berlin"},
+				{Op: diff.Ins, NewLine: "1,Alice,berlin"},
+			},
+		},
+		"DifferentNumberOfFields": {
+			a: []string{"1,alice\n"},
+			b: []string{"1,alice,\n"},
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "1,alice\n"},
+				{Op: diff.Ins, NewLine: "1,alice,\n"},
+			},
+		},
+		"TrailingNewlineNotInField": {
+			a: []string{"1,alice\n"},
+			b: []string{"1,Alice"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "1,alice\n", NewLine: "1,Alice"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines(test.a, test.b, diff.WithFieldEqual(",", eq))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Lines() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
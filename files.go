@@ -0,0 +1,107 @@
+package diff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrBinary is returned when diffing content that looks binary as reported by [IsBinary], as
+// its lines are meaningless.
+var ErrBinary = errors.New("diff: binary content")
+
+// binarySniffLen is the number of bytes [IsBinary] inspects, the same as git.
+const binarySniffLen = 8000
+
+// IsBinary reports whether data looks binary as its first 8000 bytes contain a NUL byte, the
+// heuristic used by git and GNU diff.
+func IsBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), binarySniffLen)], 0) >= 0
+}
+
+// Readers reads a and b to their end and computes the edits transforming the lines of a into the
+// lines of b like [Lines], for diffing content that is not in a file like an HTTP response body.
+// Lines keep their trailing newline. It returns [ErrBinary] if a or b looks binary.
+func Readers(a, b io.Reader, opts ...LinesOption) ([]Edit, error) {
+	oldData, err := io.ReadAll(a)
+	if err != nil {
+		return nil, err
+	}
+	newData, err := io.ReadAll(b)
+	if err != nil {
+		return nil, err
+	}
+	if IsBinary(oldData) || IsBinary(newData) {
+		return nil, ErrBinary
+	}
+	return Lines(splitLines(string(oldData)), splitLines(string(newData)), opts...), nil
+}
+
+// ReadLines reads r to its end and splits its content into lines the way [Readers] does, for
+// diffing the lines of content that is not in a file with options like [Lines]. Lines keep their
+// trailing newline.
+func ReadLines(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(string(data)), nil
+}
+
+// splitLines splits s into lines each keeping its trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Files reads the files at oldPath and newPath and computes the edits transforming the lines of
+// the old file into the lines of the new file like [Readers].
+func Files(oldPath, newPath string, opts ...LinesOption) ([]Edit, error) {
+	oldFile, err := os.Open(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	defer oldFile.Close()
+	newFile, err := os.Open(newPath)
+	if err != nil {
+		return nil, err
+	}
+	defer newFile.Close()
+	return Readers(oldFile, newFile, opts...)
+}
+
+// FilesHashed reads the files at oldPath and newPath and computes the edits transforming the
+// lines of the old file into the lines of the new file like [Readers]. It also returns the
+// SHA-256 of the raw content of each file, computed while reading it, for keying a cache of diff
+// results. The hashes are also returned along with [ErrBinary], so binary files can be compared
+// by their hashes.
+func FilesHashed(oldPath, newPath string, opts ...LinesOption) (edits []Edit, oldHash, newHash [32]byte, err error) {
+	oldFile, err := os.Open(oldPath)
+	if err != nil {
+		return nil, oldHash, newHash, err
+	}
+	defer oldFile.Close()
+	newFile, err := os.Open(newPath)
+	if err != nil {
+		return nil, oldHash, newHash, err
+	}
+	defer newFile.Close()
+
+	oldHasher, newHasher := sha256.New(), sha256.New()
+	edits, err = Readers(io.TeeReader(oldFile, oldHasher), io.TeeReader(newFile, newHasher), opts...)
+	if err != nil && err != ErrBinary {
+		return nil, oldHash, newHash, err
+	}
+	oldHasher.Sum(oldHash[:0])
+	newHasher.Sum(newHash[:0])
+	return edits, oldHash, newHash, err
+}
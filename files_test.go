@@ -0,0 +1,224 @@
+package diff_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestIsBinary(t *testing.T) {
+	tests := map[string]struct {
+		data []byte
+		want bool
+	}{
+		"Empty":           {data: nil, want: false},
+		"Text":            {data: []byte("a\nb\r\n\tc"), want: false},
+		"UTF8":            {data: []byte("äöü €\n"), want: false},
+		"NUL":             {data: []byte("a\x00b"), want: true},
+		"NULAtEndOfSniff": {data: append(bytes.Repeat([]byte("a"), 7999), 0), want: true},
+		"NULAfterSniff":   {data: append(bytes.Repeat([]byte("a"), 8000), 0), want: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := diff.IsBinary(test.data); got != test.want {
+				t.Errorf("IsBinary() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReaders(t *testing.T) {
+	edits, err := diff.Readers(strings.NewReader("a\nb\nc\n"), bytes.NewBufferString("a\nx\nc"))
+	if err != nil {
+		t.Fatalf("Readers() error: %v", err)
+	}
+	want := diff.Lines([]string{"a\n", "b\n", "c\n"}, []string{"a\n", "x\n", "c"})
+	if !slices.Equal(edits, want) {
+		t.Errorf("Readers() = %v, want %v", edits, want)
+	}
+
+	t.Run("Options", func(t *testing.T) {
+		edits, err := diff.Readers(strings.NewReader("a  b\n"), strings.NewReader("a b\n"), diff.WithIgnoreSpaceChange())
+		if err != nil {
+			t.Fatalf("Readers() error: %v", err)
+		}
+		want := []diff.Edit{{Op: diff.Eq, OldLine: "a  b\n", NewLine: "a b\n"}}
+		if !slices.Equal(edits, want) {
+			t.Errorf("Readers() = %v, want %v", edits, want)
+		}
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		_, err := diff.Readers(strings.NewReader("a\n"), strings.NewReader("a\x00b\n"))
+		if !errors.Is(err, diff.ErrBinary) {
+			t.Errorf("Readers() error = %v, want %v", err, diff.ErrBinary)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		errRead := errors.New("read failed")
+		_, err := diff.Readers(strings.NewReader("a\n"), errReader{errRead})
+		if !errors.Is(err, errRead) {
+			t.Errorf("Readers() error = %v, want %v", err, errRead)
+		}
+	})
+}
+
+// errReader is an io.Reader failing with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestReadLines(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want []string
+	}{
+		"Empty": {},
+		"TrailingNewline": {
+			in:   "a\nb\n",
+			want: []string{"a\n", "b\n"},
+		},
+		"NoTrailingNewline": {
+			in:   "a\nb",
+			want: []string{"a\n", "b"},
+		},
+		"BlankLines": {
+			in:   "\n\n",
+			want: []string{"\n", "\n"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := diff.ReadLines(strings.NewReader(test.in))
+			if err != nil {
+				t.Fatalf("ReadLines() error: %v", err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("ReadLines() = %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		errRead := errors.New("read failed")
+		_, err := diff.ReadLines(errReader{errRead})
+		if !errors.Is(err, errRead) {
+			t.Errorf("ReadLines() error = %v, want %v", err, errRead)
+		}
+	})
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.txt")
+	newPath := filepath.Join(dir, "new.txt")
+	if err := os.WriteFile(oldPath, []byte("a\nb\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newPath, []byte("a\nx\nc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	edits, err := diff.Files(oldPath, newPath)
+	if err != nil {
+		t.Fatalf("Files() error: %v", err)
+	}
+	want := diff.Lines([]string{"a\n", "b\n", "c\n"}, []string{"a\n", "x\n", "c"})
+	if !slices.Equal(edits, want) {
+		t.Errorf("Files() = %v, want %v", edits, want)
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := diff.Files(oldPath, filepath.Join(dir, "missing.txt"))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Files() error = %v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func TestFilesHashed(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.txt")
+	newPath := filepath.Join(dir, "new.txt")
+	oldData := []byte("a\nb\nc\n")
+	newData := []byte("a\nx\nc")
+	if err := os.WriteFile(oldPath, oldData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newPath, newData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	edits, oldHash, newHash, err := diff.FilesHashed(oldPath, newPath)
+	if err != nil {
+		t.Fatalf("FilesHashed() error: %v", err)
+	}
+	want := diff.Lines([]string{"a\n", "b\n", "c\n"}, []string{"a\n", "x\n", "c"})
+	if !slices.Equal(edits, want) {
+		t.Errorf("FilesHashed() edits = %v, want %v", edits, want)
+	}
+	if oldHash != sha256.Sum256(oldData) {
+		t.Errorf("FilesHashed() oldHash = %x, want %x", oldHash, sha256.Sum256(oldData))
+	}
+	if newHash != sha256.Sum256(newData) {
+		t.Errorf("FilesHashed() newHash = %x, want %x", newHash, sha256.Sum256(newData))
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		empty := filepath.Join(dir, "empty.txt")
+		if err := os.WriteFile(empty, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		edits, oldHash, _, err := diff.FilesHashed(empty, oldPath)
+		if err != nil {
+			t.Fatalf("FilesHashed() error: %v", err)
+		}
+		if len(edits) != 3 {
+			t.Errorf("FilesHashed() edits = %v, want 3 insertions", edits)
+		}
+		if oldHash != sha256.Sum256(nil) {
+			t.Errorf("FilesHashed() oldHash = %x, want %x", oldHash, sha256.Sum256(nil))
+		}
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		binData := []byte("\x7fELF\x00\x01")
+		bin := filepath.Join(dir, "bin")
+		if err := os.WriteFile(bin, binData, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		edits, oldHash, newHash, err := diff.FilesHashed(oldPath, bin)
+		if !errors.Is(err, diff.ErrBinary) {
+			t.Fatalf("FilesHashed() error = %v, want %v", err, diff.ErrBinary)
+		}
+		if edits != nil {
+			t.Errorf("FilesHashed() edits = %v, want none", edits)
+		}
+		if oldHash != sha256.Sum256(oldData) || newHash != sha256.Sum256(binData) {
+			t.Errorf("FilesHashed() hashes = %x, %x, want hashes of the content", oldHash, newHash)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, _, _, err := diff.FilesHashed(filepath.Join(dir, "missing.txt"), newPath)
+		if !os.IsNotExist(err) {
+			t.Errorf("FilesHashed() error = %v, want not exist", err)
+		}
+	})
+}
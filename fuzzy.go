@@ -0,0 +1,103 @@
+package diff
+
+// WithFuzzyThreshold pairs up deleted and inserted lines that are similar, so a line with a small
+// edit is shown as a deletion directly followed by its insertion instead of being separated from
+// it by unrelated changes. Within each run of changes, a deleted line is paired with the following
+// inserted line of the highest [Ratio] if that ratio is at least threshold. Pairs keep the order of
+// both sequences. A threshold of 0 disables pairing. It panics if threshold is not within [0, 1].
+//
+// Pairing compares every deleted line of a run of changes with the inserted lines of that run,
+// each comparison computing a rune-level diff. Runs of many long changed lines therefore add
+// considerable cost on top of computing the edit script.
+func WithFuzzyThreshold(threshold float64) LinesOption {
+	if threshold < 0 || threshold > 1 {
+		panic("diff: fuzzy threshold not within [0, 1]")
+	}
+	return func(conf *linesConfig) {
+		conf.fuzzyThreshold = threshold
+	}
+}
+
+// Ratio returns a measure of the similarity of a and b in the range [0, 1] like Python's
+// difflib.SequenceMatcher.ratio. It is 2*M/T where M is the number of runes in the longest
+// common subsequence of a and b and T is the total number of runes in both. Ratio returns 1 if
+// both are empty.
+func Ratio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	total := len(ra) + len(rb)
+	if total == 0 {
+		return 1
+	}
+	var matches int
+	for _, op := range editOps(len(ra), len(rb), func(x, y int) bool { return ra[x] == rb[y] }) {
+		if op == Eq {
+			matches++
+		}
+	}
+	return 2 * float64(matches) / float64(total)
+}
+
+// pairSimilar reorders each run of changes in edits so deleted lines are directly followed by
+// their most similar inserted line with a [Ratio] of at least threshold.
+func pairSimilar(edits []Edit, threshold float64) []Edit {
+	result := make([]Edit, 0, len(edits))
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Eq {
+			result = append(result, edits[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].Op != Eq {
+			j++
+		}
+		result = append(result, pairRun(edits[i:j], threshold)...)
+		i = j
+	}
+	return result
+}
+
+// pairRun pairs the deleted and inserted lines of a single run of changes.
+func pairRun(run []Edit, threshold float64) []Edit {
+	var dels, inss []Edit
+	for _, e := range run {
+		if e.Op == Del {
+			dels = append(dels, e)
+		} else {
+			inss = append(inss, e)
+		}
+	}
+
+	type pair struct{ del, ins int }
+	var pairs []pair
+	next := 0 // first inserted line available for pairing
+	for d, del := range dels {
+		best, bestRatio := -1, threshold
+		for k := next; k < len(inss); k++ {
+			if r := Ratio(del.OldLine, inss[k].NewLine); r >= bestRatio {
+				if best < 0 || r > bestRatio {
+					best, bestRatio = k, r
+				}
+			}
+		}
+		if best >= 0 {
+			pairs = append(pairs, pair{del: d, ins: best})
+			next = best + 1
+		}
+	}
+	if len(pairs) == 0 {
+		return run
+	}
+
+	result := make([]Edit, 0, len(run))
+	var d, k int
+	for _, p := range pairs {
+		result = append(result, dels[d:p.del]...)
+		result = append(result, inss[k:p.ins]...)
+		result = append(result, dels[p.del], inss[p.ins])
+		d, k = p.del+1, p.ins+1
+	}
+	result = append(result, dels[d:]...)
+	result = append(result, inss[k:]...)
+	return result
+}
@@ -0,0 +1,83 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestRatio(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want float64
+	}{
+		"BothEmpty":   {a: "", b: "", want: 1},
+		"OneEmpty":    {a: "abc", b: "", want: 0},
+		"Equal":       {a: "abc", b: "abc", want: 1},
+		"Different":   {a: "abc", b: "xyz", want: 0},
+		"HalfMatches": {a: "abcd", b: "abxy", want: 0.5},
+		"Runes":       {a: "héllo", b: "hällo", want: 0.8},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Ratio(test.a, test.b)
+			if got != test.want {
+				t.Errorf("Ratio(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLinesFuzzyThreshold(t *testing.T) {
+	oldLines := []string{"start\n", "alpha\n", "hello world\n", "end\n"}
+	newLines := []string{"start\n", "something else\n", "hello world!\n", "end\n"}
+
+	tests := map[string]struct {
+		opts []diff.LinesOption
+		want []diff.Edit
+	}{
+		"Disabled": {
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "start\n", NewLine: "start\n"},
+				{Op: diff.Del, OldLine: "alpha\n"},
+				{Op: diff.Del, OldLine: "hello world\n"},
+				{Op: diff.Ins, NewLine: "something else\n"},
+				{Op: diff.Ins, NewLine: "hello world!\n"},
+				{Op: diff.Eq, OldLine: "end\n", NewLine: "end\n"},
+			},
+		},
+		"PairsSimilarLines": {
+			opts: []diff.LinesOption{diff.WithFuzzyThreshold(0.8)},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "start\n", NewLine: "start\n"},
+				{Op: diff.Del, OldLine: "alpha\n"},
+				{Op: diff.Ins, NewLine: "something else\n"},
+				{Op: diff.Del, OldLine: "hello world\n"},
+				{Op: diff.Ins, NewLine: "hello world!\n"},
+				{Op: diff.Eq, OldLine: "end\n", NewLine: "end\n"},
+			},
+		},
+		"BelowThreshold": {
+			opts: []diff.LinesOption{diff.WithFuzzyThreshold(0.99)},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "start\n", NewLine: "start\n"},
+				{Op: diff.Del, OldLine: "alpha\n"},
+				{Op: diff.Del, OldLine: "hello world\n"},
+				{Op: diff.Ins, NewLine: "something else\n"},
+				{Op: diff.Ins, NewLine: "hello world!\n"},
+				{Op: diff.Eq, OldLine: "end\n", NewLine: "end\n"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines(oldLines, newLines, test.opts...)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("diff.Lines():\ngot:  %v\nwant: %v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/teleivo/diff
 
 go 1.25.5
+
+require golang.org/x/text v0.30.0
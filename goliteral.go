@@ -0,0 +1,42 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteGoLiteral writes the edits to w as a Go composite literal of type []diff.Edit with one
+// edit per line, such as
+//
+//	[]diff.Edit{
+//		{Op: diff.Del, OldLine: "foo\n"},
+//	}
+//
+// for pasting expected edits into table-driven tests. Strings are quoted like [strconv.Quote]
+// and lines are only written for the fields used by the op, like in [Edit].
+func WriteGoLiteral(w io.Writer, edits []Edit) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString("[]diff.Edit{\n"); err != nil {
+		return err
+	}
+	for _, e := range edits {
+		var err error
+		switch e.Op {
+		case Eq:
+			_, err = fmt.Fprintf(bw, "\t{Op: diff.Eq, OldLine: %s, NewLine: %s},\n", strconv.Quote(e.OldLine), strconv.Quote(e.NewLine))
+		case Del:
+			_, err = fmt.Fprintf(bw, "\t{Op: diff.Del, OldLine: %s},\n", strconv.Quote(e.OldLine))
+		case Ins:
+			_, err = fmt.Fprintf(bw, "\t{Op: diff.Ins, NewLine: %s},\n", strconv.Quote(e.NewLine))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := bw.WriteString("}\n"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,52 @@
+package diff_test
+
+import (
+	"go/parser"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteGoLiteral(t *testing.T) {
+	tests := map[string]struct {
+		edits []diff.Edit
+		want  string
+	}{
+		"Empty": {
+			want: "[]diff.Edit{\n}\n",
+		},
+		"AllOps": {
+			edits: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Del, OldLine: "say \"hi\"\n"},
+				{Op: diff.Ins, NewLine: "\ttab\\`"},
+			},
+			want: "[]diff.Edit{\n" +
+				"\t{Op: diff.Eq, OldLine: \"a\\n\", NewLine: \"a\\n\"},\n" +
+				"\t{Op: diff.Del, OldLine: \"say \\\"hi\\\"\\n\"},\n" +
+				"\t{Op: diff.Ins, NewLine: \"\\ttab\\\\`\"},\n" +
+				"}\n",
+		},
+		"EqDifferingLines": {
+			edits: []diff.Edit{{Op: diff.Eq, OldLine: "a \n", NewLine: "a\n"}},
+			want:  "[]diff.Edit{\n\t{Op: diff.Eq, OldLine: \"a \\n\", NewLine: \"a\\n\"},\n}\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := diff.WriteGoLiteral(&sb, test.edits); err != nil {
+				t.Fatalf("WriteGoLiteral() error: %v", err)
+			}
+			got := sb.String()
+			if got != test.want {
+				t.Errorf("WriteGoLiteral() =\n%s\nwant:\n%s", got, test.want)
+			}
+			if _, err := parser.ParseExpr(got); err != nil {
+				t.Errorf("WriteGoLiteral() is not a valid Go expression: %v", err)
+			}
+		})
+	}
+}
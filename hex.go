@@ -0,0 +1,164 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hexRowWidth is the number of bytes per row in [WriteHexDiff].
+const hexRowWidth = 16
+
+// hexCell is one aligned column of a hex diff. A side without a byte in the column has a
+// negative value.
+type hexCell struct {
+	old, new int
+}
+
+// WriteHexDiff writes a hexdump-style diff of a and b to w. The bytes are matched using the same
+// algorithm as [Lines] so insertions and deletions shift the alignment instead of marking every
+// following byte as changed. Within a run of changes, deleted and inserted bytes are paired up
+// column by column.
+//
+// The aligned bytes are written in rows of 16 columns consisting of a marker, the offset of the
+// first byte of the row, the bytes in hex and as ASCII. Rows without changes are marked with a
+// space and show the offset in a. A row with changes is written twice, marked with - for a and +
+// for b, followed by a row of ^^ under the changed columns. A column without a byte on one side is
+// left blank. Runs of unchanged rows that are not adjacent to a changed row are collapsed into a
+// single * line. Nothing is written if a and b are equal.
+func WriteHexDiff(w io.Writer, a, b []byte) error {
+	ops := editOps(len(a), len(b), func(x, y int) bool {
+		return a[x] == b[y]
+	})
+	cells := alignHex(ops, a, b)
+	if !hexRowChanged(cells) {
+		return nil
+	}
+
+	bw := bufio.NewWriter(w)
+	var offOld, offNew int
+	collapsed := false
+	for start := 0; start < len(cells); start += hexRowWidth {
+		row := cells[start:min(start+hexRowWidth, len(cells))]
+		changed := hexRowChanged(row)
+		if !changed && !hexRowChanged(cells[max(0, start-hexRowWidth):start]) &&
+			!hexRowChanged(cells[min(start+hexRowWidth, len(cells)):min(start+2*hexRowWidth, len(cells))]) {
+			if !collapsed {
+				if _, err := bw.WriteString("*\n"); err != nil {
+					return err
+				}
+				collapsed = true
+			}
+		} else {
+			collapsed = false
+			if err := writeHexRows(bw, row, changed, offOld, offNew); err != nil {
+				return err
+			}
+		}
+		for _, c := range row {
+			if c.old >= 0 {
+				offOld++
+			}
+			if c.new >= 0 {
+				offNew++
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// alignHex turns ops into aligned columns pairing up the deleted and inserted bytes of each run
+// of changes.
+func alignHex(ops []OpType, a, b []byte) []hexCell {
+	cells := make([]hexCell, 0, len(ops))
+	var x, y int
+	for i := 0; i < len(ops); {
+		if ops[i] == Eq {
+			cells = append(cells, hexCell{old: int(a[x]), new: int(b[y])})
+			x++
+			y++
+			i++
+			continue
+		}
+		var dels, inss int
+		for ; i < len(ops) && ops[i] != Eq; i++ {
+			if ops[i] == Del {
+				dels++
+			} else {
+				inss++
+			}
+		}
+		for j := range max(dels, inss) {
+			c := hexCell{old: -1, new: -1}
+			if j < dels {
+				c.old = int(a[x+j])
+			}
+			if j < inss {
+				c.new = int(b[y+j])
+			}
+			cells = append(cells, c)
+		}
+		x += dels
+		y += inss
+	}
+	return cells
+}
+
+func hexRowChanged(row []hexCell) bool {
+	for _, c := range row {
+		if c.old != c.new {
+			return true
+		}
+	}
+	return false
+}
+
+func writeHexRows(w *bufio.Writer, row []hexCell, changed bool, offOld, offNew int) error {
+	if !changed {
+		return writeHexRow(w, ' ', offOld, row, func(c hexCell) int { return c.old })
+	}
+	if err := writeHexRow(w, '-', offOld, row, func(c hexCell) int { return c.old }); err != nil {
+		return err
+	}
+	if err := writeHexRow(w, '+', offNew, row, func(c hexCell) int { return c.new }); err != nil {
+		return err
+	}
+	var marker strings.Builder
+	marker.WriteString(strings.Repeat(" ", 11))
+	for i, c := range row {
+		if i > 0 {
+			marker.WriteByte(' ')
+		}
+		if c.old != c.new {
+			marker.WriteString("^^")
+		} else {
+			marker.WriteString("  ")
+		}
+	}
+	_, err := w.WriteString(strings.TrimRight(marker.String(), " ") + "\n")
+	return err
+}
+
+func writeHexRow(w *bufio.Writer, marker byte, offset int, row []hexCell, side func(hexCell) int) error {
+	var hex, ascii strings.Builder
+	for i, c := range row {
+		if i > 0 {
+			hex.WriteByte(' ')
+		}
+		v := side(c)
+		if v < 0 {
+			hex.WriteString("  ")
+			ascii.WriteByte(' ')
+			continue
+		}
+		fmt.Fprintf(&hex, "%02x", v)
+		if v >= 0x20 && v < 0x7f {
+			ascii.WriteByte(byte(v))
+		} else {
+			ascii.WriteByte('.')
+		}
+	}
+	_, err := fmt.Fprintf(w, "%c%08x  %-*s  |%s|\n", marker, offset, hexRowWidth*3-1, hex.String(), ascii.String())
+	return err
+}
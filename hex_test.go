@@ -0,0 +1,73 @@
+package diff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteHexDiff(t *testing.T) {
+	alphabet := func(n int) []byte {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte('A' + i%26)
+		}
+		return b
+	}
+	changed := alphabet(80)
+	changed[70] = 'z'
+
+	tests := map[string]struct {
+		a, b []byte
+		want string
+	}{
+		"Equal": {
+			a:    []byte("same"),
+			b:    []byte("same"),
+			want: "",
+		},
+		"ChangedAndInserted": {
+			a: []byte("Hello world"),
+			b: []byte("Hallo world!"),
+			want: "-00000000  48 65 6c 6c 6f 20 77 6f 72 6c 64" + spaces(15) + "  |Hello world |\n" +
+				"+00000000  48 61 6c 6c 6f 20 77 6f 72 6c 64 21" + spaces(12) + "  |Hallo world!|\n" +
+				"              ^^                            ^^\n",
+		},
+		"InsertionShiftsAlignment": {
+			a: []byte("abcdefghijklmnopqrstuvwxyz"),
+			b: []byte("0abcdefghijklmnopqrstuvwxyz"),
+			want: "-00000000     61 62 63 64 65 66 67 68 69 6a 6b 6c 6d 6e 6f  | abcdefghijklmno|\n" +
+				"+00000000  30 61 62 63 64 65 66 67 68 69 6a 6b 6c 6d 6e 6f  |0abcdefghijklmno|\n" +
+				"           ^^\n" +
+				" 0000000f  70 71 72 73 74 75 76 77 78 79 7a" + spaces(15) + "  |pqrstuvwxyz|\n",
+		},
+		"CollapsesUnchangedRows": {
+			a: alphabet(80),
+			b: changed,
+			want: "*\n" +
+				" 00000030  57 58 59 5a 41 42 43 44 45 46 47 48 49 4a 4b 4c  |WXYZABCDEFGHIJKL|\n" +
+				"-00000040  4d 4e 4f 50 51 52 53 54 55 56 57 58 59 5a 41 42  |MNOPQRSTUVWXYZAB|\n" +
+				"+00000040  4d 4e 4f 50 51 52 7a 54 55 56 57 58 59 5a 41 42  |MNOPQRzTUVWXYZAB|\n" +
+				"                             ^^\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := diff.WriteHexDiff(&buf, test.a, test.b)
+			if err != nil {
+				t.Fatalf("WriteHexDiff() error: %v", err)
+			}
+			got := buf.String()
+			if got != test.want {
+				t.Errorf("WriteHexDiff() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func spaces(n int) string {
+	return string(bytes.Repeat([]byte(" "), n))
+}
@@ -0,0 +1,71 @@
+package diff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// IdentifiedHunk is a [Hunk] together with an ID derived from its content.
+type IdentifiedHunk struct {
+	ID string
+	Hunk
+}
+
+// HunksWithIDs groups edits into hunks with the given number of context lines like [Hunks] and
+// gives each an ID, the hex encoded SHA-256 of its position and lines. The ID is stable across
+// recomputing the same diff and changes if a hunk moves or its lines change, so a client can
+// refer to hunks by ID and a server can detect that the diff changed in the meantime: it
+// recomputes the hunks and applies the IDs accepted by the client using [ApplyHunks], which fails
+// if an ID is no longer among them. It panics if context is negative.
+func HunksWithIDs(edits []Edit, context int) []IdentifiedHunk {
+	hunks := Hunks(edits, context)
+	if len(hunks) == 0 {
+		return nil
+	}
+	result := make([]IdentifiedHunk, len(hunks))
+	for i, h := range hunks {
+		result[i] = IdentifiedHunk{ID: hunkID(h), Hunk: h}
+	}
+	return result
+}
+
+// ApplyHunks applies the hunks whose ID is in accepted to a, the old sequence they were computed
+// from by [HunksWithIDs], and returns the new sequence with only the accepted changes. The hunks
+// must match a exactly at their position: unlike [ApplyFuzzy] with a fuzz, they are not searched
+// for elsewhere, as a that changed since the hunks were computed needs a new review rather than
+// a patch at a drifted position. It returns an error if an accepted ID is not one of hunks, as
+// when the diff changed since the client received its hunks, or if a hunk does not match a.
+func ApplyHunks(a []string, hunks []IdentifiedHunk, accepted []string) ([]string, error) {
+	var apply []Hunk
+	for _, h := range hunks {
+		if slices.Contains(accepted, h.ID) {
+			apply = append(apply, h.Hunk)
+		}
+	}
+	for _, id := range accepted {
+		if !slices.ContainsFunc(hunks, func(h IdentifiedHunk) bool { return h.ID == id }) {
+			return nil, fmt.Errorf("diff: no hunk with ID %s", id)
+		}
+	}
+	return ApplyFuzzy(a, apply, 0)
+}
+
+// hunkID returns the ID of h. Lines are quoted so no two hunks hash the same input.
+func hunkID(h Hunk) string {
+	s := sha256.New()
+	fmt.Fprintf(s, "%d,%d %d,%d\n", h.OldStart, h.OldCount, h.NewStart, h.NewCount)
+	for _, e := range h.Edits {
+		switch e.Op {
+		case Eq:
+			fmt.Fprintf(s, " %s %s\n", strconv.Quote(e.OldLine), strconv.Quote(e.NewLine))
+		case Del:
+			fmt.Fprintf(s, "-%s\n", strconv.Quote(e.OldLine))
+		case Ins:
+			fmt.Fprintf(s, "+%s\n", strconv.Quote(e.NewLine))
+		}
+	}
+	return hex.EncodeToString(s.Sum(nil))
+}
@@ -0,0 +1,86 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestHunksWithIDs(t *testing.T) {
+	a := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	b := []string{"a", "x", "c", "d", "e", "f", "g", "y"}
+
+	hunks := diff.HunksWithIDs(diff.Lines(a, b), 0)
+	if len(hunks) != 2 {
+		t.Fatalf("HunksWithIDs() returned %d hunks, want 2", len(hunks))
+	}
+	if hunks[0].ID == hunks[1].ID {
+		t.Errorf("HunksWithIDs() IDs are equal: %s", hunks[0].ID)
+	}
+
+	t.Run("StableAcrossRecomputation", func(t *testing.T) {
+		again := diff.HunksWithIDs(diff.Lines(slices.Clone(a), slices.Clone(b)), 0)
+		for i := range hunks {
+			if again[i].ID != hunks[i].ID {
+				t.Errorf("HunksWithIDs()[%d].ID = %s, want %s", i, again[i].ID, hunks[i].ID)
+			}
+		}
+	})
+
+	t.Run("ChangesWithPosition", func(t *testing.T) {
+		moved := diff.HunksWithIDs(diff.Lines(append([]string{"new"}, a...), append([]string{"new"}, b...)), 0)
+		if moved[0].ID == hunks[0].ID {
+			t.Errorf("HunksWithIDs() ID did not change for a moved hunk")
+		}
+	})
+
+	t.Run("ChangesWithContent", func(t *testing.T) {
+		changed := slices.Clone(b)
+		changed[1] = "z"
+		other := diff.HunksWithIDs(diff.Lines(a, changed), 0)
+		if other[0].ID == hunks[0].ID {
+			t.Errorf("HunksWithIDs() ID did not change for a changed hunk")
+		}
+		if other[1].ID != hunks[1].ID {
+			t.Errorf("HunksWithIDs() ID of the unchanged hunk = %s, want %s", other[1].ID, hunks[1].ID)
+		}
+	})
+
+	t.Run("ApplyAccepted", func(t *testing.T) {
+		got, err := diff.ApplyHunks(a, hunks, []string{hunks[1].ID})
+		if err != nil {
+			t.Fatalf("ApplyHunks() error: %v", err)
+		}
+		want := []string{"a", "b", "c", "d", "e", "f", "g", "y"}
+		if !slices.Equal(got, want) {
+			t.Errorf("ApplyHunks() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("ApplyNone", func(t *testing.T) {
+		got, err := diff.ApplyHunks(a, hunks, nil)
+		if err != nil {
+			t.Fatalf("ApplyHunks() error: %v", err)
+		}
+		if !slices.Equal(got, a) {
+			t.Errorf("ApplyHunks() = %q, want %q", got, a)
+		}
+	})
+
+	t.Run("ApplyUnknownID", func(t *testing.T) {
+		changed := slices.Clone(b)
+		changed[1] = "z"
+		current := diff.HunksWithIDs(diff.Lines(a, changed), 0)
+		if _, err := diff.ApplyHunks(a, current, []string{hunks[0].ID}); err == nil {
+			t.Error("ApplyHunks() expected error for an ID of a changed hunk, got nil")
+		}
+	})
+
+	t.Run("ApplyToChangedOld", func(t *testing.T) {
+		drifted := append([]string{"new"}, a...)
+		if _, err := diff.ApplyHunks(drifted, hunks, []string{hunks[0].ID}); err == nil {
+			t.Error("ApplyHunks() expected error for hunks not matching at their position, got nil")
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Hunk is a group of contiguous changes with surrounding context lines as written by [Write].
+type Hunk struct {
+	OldStart, OldCount int // range in the old sequence as in a unified hunk header
+	NewStart, NewCount int // range in the new sequence as in a unified hunk header
+	Edits              []Edit
+}
+
+// Hunks groups edits into hunks with the given number of context lines like [Write]. The edits
+// of each hunk are a subslice of edits. The options grouping edits into hunks and numbering them
+// apply like for [Write], so callers rendering hunks in their own way follow the same settings:
+// [WithContextBefore], [WithContextAfter], [WithExtraContext], [WithSeparateHunks],
+// [WithSuppressWhitespaceOnlyHunks] and [WithLineBase]. Other options are ignored. It panics if
+// context is negative.
+func Hunks(edits []Edit, context int, opts ...Option) []Hunk {
+	hunks, conf := groupHunks(edits, context, opts)
+	if len(hunks) == 0 {
+		return nil
+	}
+	result := make([]Hunk, len(hunks))
+	for i, h := range hunks {
+		result[i] = Hunk{
+			OldStart: rebase(h.startOld, h.countOld, conf.lineBase),
+			OldCount: h.countOld,
+			NewStart: rebase(h.startNew, h.countNew, conf.lineBase),
+			NewCount: h.countNew,
+			Edits:    edits[h.start:h.end],
+		}
+	}
+	return result
+}
+
+// groupHunks groups edits into hunks with the given number of context lines and opts like
+// [Write]. It panics if context is negative.
+func groupHunks(edits []Edit, context int, opts []Option) ([]hunk, *config) {
+	conf := newConfig(append([]Option{WithContext(context)}, opts...))
+	hunks, _ := conf.hunks(edits)
+	return hunks, conf
+}
+
+// Header returns the header of h in unified format as written by [Write], such as
+// "@@ -1,3 +1,4 @@".
+func (h Hunk) Header() string {
+	return hunkHeader(h.OldStart, h.OldCount, h.NewStart, h.NewCount)
+}
+
+// WriteHunk writes only the hunk at index of the hunks of edits with the given number of context
+// lines in unified format, its header followed by its lines as written by [Write], like for
+// showing a diff one hunk at a time. It returns an error if there is no hunk at index. It panics
+// if context is negative.
+func WriteHunk(w io.Writer, edits []Edit, context, index int) error {
+	if context < 0 {
+		panic("diff: negative context")
+	}
+	hunks, _ := buildHunks(edits, context, context, false)
+	if index < 0 || index >= len(hunks) {
+		return fmt.Errorf("diff: hunk %d out of range of %d hunks", index, len(hunks))
+	}
+	conf := &config{context: context, before: -1, after: -1, lineBase: 1, ctxMark: ' ', header: hunkHeader}
+	bw := bufio.NewWriter(w)
+	if err := writeHunks(bw, edits, hunks[index:index+1], nil, conf, 0); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// LineEvent is a line of a diff visited by [Walk].
+type LineEvent struct {
+	Op        OpType
+	Line      string // line from the old sequence for Del, from the new sequence otherwise
+	OldNumber int    // 1-indexed line number in the old sequence, 0 for Ins
+	NewNumber int    // 1-indexed line number in the new sequence, 0 for Del
+	Hunk      int    // 0-indexed number of the hunk of the line
+	First     bool   // whether the line is the first of its hunk
+	Last      bool   // whether the line is the last of its hunk
+}
+
+// Walk calls visit for each line of the hunks of edits with the given number of context lines as
+// written by [Write], in order. It lets callers render a diff in their own way using the same
+// grouping into hunks, which opts configure like for [Hunks]. The line numbers of the events are
+// 1-indexed regardless of [WithLineBase]. It panics if context is negative.
+func Walk(edits []Edit, context int, visit func(LineEvent), opts ...Option) {
+	hunks, _ := groupHunks(edits, context, opts)
+	for i, h := range hunks {
+		oldNumber, newNumber := h.startOld, h.startNew
+		for j, e := range edits[h.start:h.end] {
+			ev := LineEvent{Op: e.Op, Line: e.NewLine, Hunk: i, First: j == 0, Last: j == h.end-h.start-1}
+			if e.Op == Del {
+				ev.Line = e.OldLine
+			}
+			if e.Op != Ins {
+				ev.OldNumber = oldNumber
+				oldNumber++
+			}
+			if e.Op != Del {
+				ev.NewNumber = newNumber
+				newNumber++
+			}
+			visit(ev)
+		}
+	}
+}
+
+// ApplyFuzzy applies hunks to a and returns the patched lines. Like patch(1), a hunk whose old
+// lines are not found at its stated position is searched for within fuzz lines before and after
+// it, trying the closest positions first. The offset of a hunk carries over to the position of
+// the next hunk so a target that drifted by a number of lines only needs to be searched for once.
+// Hunks must be ordered and not overlap. It returns an error if a hunk cannot be located. Use
+// [ApplyFuzzyWithOffsets] to also learn where each hunk was applied.
+func ApplyFuzzy(a []string, hunks []Hunk, fuzz int) ([]string, error) {
+	result, _, err := ApplyFuzzyWithOffsets(a, hunks, fuzz)
+	return result, err
+}
+
+// ApplyFuzzyWithOffsets applies hunks to a like [ApplyFuzzy] and also returns the offset at which
+// each hunk was applied, the number of lines its old lines were found after its stated position
+// or before it if negative, like the offsets reported by patch(1).
+func ApplyFuzzyWithOffsets(a []string, hunks []Hunk, fuzz int) ([]string, []int, error) {
+	var result []string
+	offsets := make([]int, len(hunks))
+	var cursor, drift int
+	for i, h := range hunks {
+		var from, to []string
+		for _, e := range h.Edits {
+			if e.Op != Ins {
+				from = append(from, e.OldLine)
+			}
+			if e.Op != Del {
+				to = append(to, e.NewLine)
+			}
+		}
+		pos := h.OldStart
+		if h.OldCount > 0 {
+			pos-- // 0-indexed position of the first old line
+		}
+		found := false
+		for _, off := range fuzzOffsets(fuzz) {
+			p := pos + drift + off
+			if p >= cursor && p+len(from) <= len(a) && slices.Equal(a[p:p+len(from)], from) {
+				pos = p
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("diff: hunk %d at line %d not found within %d lines", i+1, pos+1, fuzz)
+		}
+		offsets[i] = pos - (h.OldStart - min(h.OldCount, 1))
+		drift = offsets[i]
+		result = append(result, a[cursor:pos]...)
+		result = append(result, to...)
+		cursor = pos + len(from)
+	}
+	result = append(result, a[cursor:]...)
+	return result, offsets, nil
+}
+
+// fuzzOffsets returns the offsets 0, -1, 1, -2, 2 up to fuzz in the order they are tried.
+func fuzzOffsets(fuzz int) []int {
+	offsets := []int{0}
+	for i := 1; i <= fuzz; i++ {
+		offsets = append(offsets, -i, i)
+	}
+	return offsets
+}
@@ -0,0 +1,204 @@
+package diff_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestHunks(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Del, OldLine: "del1\n"},
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+		{Op: diff.Ins, NewLine: "ins1\n"},
+	}
+
+	got := diff.Hunks(edits, 1)
+
+	want := []diff.Hunk{
+		{OldStart: 1, OldCount: 2, NewStart: 1, NewCount: 1, Edits: edits[0:2]},
+		{OldStart: 5, OldCount: 1, NewStart: 4, NewCount: 2, Edits: edits[4:6]},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Hunks() returned %d hunks, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].OldStart != want[i].OldStart || got[i].OldCount != want[i].OldCount ||
+			got[i].NewStart != want[i].NewStart || got[i].NewCount != want[i].NewCount ||
+			!slices.Equal(got[i].Edits, want[i].Edits) {
+			t.Errorf("Hunks()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestHunksOptions(t *testing.T) {
+	edits := diff.Lines(
+		[]string{"a\n", "x\n", "b\n", "c\n", "y\n", "d\n"},
+		[]string{"a\n", "b\n", "c\n", "z\n", "d\n"},
+	)
+
+	tests := map[string]struct {
+		opts []diff.Option
+	}{
+		"Default":       {},
+		"ContextBefore": {opts: []diff.Option{diff.WithContextBefore(0)}},
+		"ContextAfter":  {opts: []diff.Option{diff.WithContextAfter(0)}},
+		"Separate":      {opts: []diff.Option{diff.WithSeparateHunks()}},
+		"LineBase":      {opts: []diff.Option{diff.WithLineBase(0)}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// the headers of the hunks are the ones written by Write with the same options
+			var want strings.Builder
+			if err := diff.Write(&want, edits, append(test.opts, diff.WithContext(1), diff.WithHeadersOnly())...); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			var got strings.Builder
+			for _, h := range diff.Hunks(edits, 1, test.opts...) {
+				got.WriteString(h.Header() + "\n")
+			}
+			if got.String() != want.String() {
+				t.Errorf("Hunks() headers =\n%s\nwant:\n%s", got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestWriteHunk(t *testing.T) {
+	old := []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n", "g\n", "h\n", "i\n"}
+	new := []string{"A\n", "b\n", "c\n", "d\n", "E\n", "f\n", "g\n", "h\n", "I\n"}
+	edits := diff.Lines(old, new)
+
+	var sb strings.Builder
+	if err := diff.WriteHunk(&sb, edits, 1, 1); err != nil {
+		t.Fatalf("WriteHunk() error: %v", err)
+	}
+	want := "@@ -4,3 +4,3 @@\n d\n-e\n+E\n f\n"
+	if got := sb.String(); got != want {
+		t.Errorf("WriteHunk() =\n%q\nwant:\n%q", got, want)
+	}
+
+	for _, index := range []int{-1, 3} {
+		if err := diff.WriteHunk(&sb, edits, 1, index); err == nil {
+			t.Errorf("WriteHunk(%d) expected error", index)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Del, OldLine: "del1\n"},
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+		{Op: diff.Ins, NewLine: "ins1\n"},
+	}
+
+	var got []diff.LineEvent
+	diff.Walk(edits, 1, func(ev diff.LineEvent) {
+		got = append(got, ev)
+	})
+
+	want := []diff.LineEvent{
+		{Op: diff.Del, Line: "del1\n", OldNumber: 1, Hunk: 0, First: true},
+		{Op: diff.Eq, Line: "a\n", OldNumber: 2, NewNumber: 1, Hunk: 0, Last: true},
+		{Op: diff.Eq, Line: "d\n", OldNumber: 5, NewNumber: 4, Hunk: 1, First: true},
+		{Op: diff.Ins, Line: "ins1\n", NewNumber: 5, Hunk: 1, Last: true},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Walk() visited\n%v\nwant\n%v", got, want)
+	}
+
+	got = nil
+	diff.Walk(edits, 1, func(ev diff.LineEvent) {
+		got = append(got, ev)
+	}, diff.WithContextBefore(0))
+	want = []diff.LineEvent{
+		{Op: diff.Del, Line: "del1\n", OldNumber: 1, Hunk: 0, First: true},
+		{Op: diff.Eq, Line: "a\n", OldNumber: 2, NewNumber: 1, Hunk: 0, Last: true},
+		{Op: diff.Ins, Line: "ins1\n", NewNumber: 5, Hunk: 1, First: true, Last: true},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Walk() with options visited\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestApplyFuzzy(t *testing.T) {
+	lines := func(s ...string) []string {
+		for i := range s {
+			s[i] += "\n"
+		}
+		return s
+	}
+	a := lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12")
+	b := lines("1", "2", "3", "4", "five", "6", "7", "8", "9", "10", "11", "twelve")
+	hunks := diff.Hunks(diff.Lines(a, b), 1)
+
+	tests := map[string]struct {
+		target      []string
+		fuzz        int
+		want        []string
+		wantOffsets []int
+		wantErr     bool
+	}{
+		"ExactPosition": {
+			target:      a,
+			fuzz:        0,
+			want:        b,
+			wantOffsets: []int{0, 0},
+		},
+		"ShiftedDown": {
+			target:      append(lines("new1", "new2"), a...),
+			fuzz:        2,
+			want:        append(lines("new1", "new2"), b...),
+			wantOffsets: []int{2, 2},
+		},
+		"ShiftedUp": {
+			target:      a[1:],
+			fuzz:        1,
+			want:        b[1:],
+			wantOffsets: []int{-1, -1},
+		},
+		"ShiftedBeyondFuzz": {
+			target:  append(lines("new1", "new2"), a...),
+			fuzz:    1,
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, offsets, err := diff.ApplyFuzzyWithOffsets(test.target, hunks, test.fuzz)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("ApplyFuzzyWithOffsets() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyFuzzyWithOffsets() unexpected error: %v", err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("ApplyFuzzyWithOffsets() = %q, want %q", got, test.want)
+			}
+			if !slices.Equal(offsets, test.wantOffsets) {
+				t.Errorf("ApplyFuzzyWithOffsets() offsets = %v, want %v", offsets, test.wantOffsets)
+			}
+
+			got, err = diff.ApplyFuzzy(test.target, hunks, test.fuzz)
+			if err != nil {
+				t.Fatalf("ApplyFuzzy() unexpected error: %v", err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("ApplyFuzzy() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
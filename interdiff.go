@@ -0,0 +1,49 @@
+package diff
+
+// LineRange is a range of lines.
+type LineRange struct {
+	Start, End int // 1-indexed inclusive range of lines
+}
+
+// NewlyChanged returns the ranges of lines that are newly changed in the revision of newEdits
+// compared to the revision of oldEdits, where both edit scripts transform the same base, like two
+// versions of a pull request before and after a force-push. A line is changed in a revision if
+// it is inserted into the base. It is newly changed if it is changed and not an unmodified copy
+// of a changed line of the old revision, as found by comparing both revisions using [Lines]. The
+// ranges refer to the lines of the new revision and are coalesced, so adjacent lines form a
+// single range.
+func NewlyChanged(oldEdits, newEdits []Edit) []LineRange {
+	oldRev, oldChanged := revision(oldEdits)
+	newRev, newChanged := revision(newEdits)
+
+	var ranges []LineRange
+	var x, y int
+	for _, e := range Lines(oldRev, newRev) {
+		if e.Op != Del {
+			seen := e.Op == Eq && oldChanged[x]
+			if newChanged[y] && !seen {
+				if n := len(ranges); n > 0 && ranges[n-1].End == y {
+					ranges[n-1].End++
+				} else {
+					ranges = append(ranges, LineRange{Start: y + 1, End: y + 1})
+				}
+			}
+			y++
+		}
+		if e.Op != Ins {
+			x++
+		}
+	}
+	return ranges
+}
+
+// revision returns the lines of the new sequence of edits and whether each of them is inserted.
+func revision(edits []Edit) (lines []string, changed []bool) {
+	for _, e := range edits {
+		if e.Op != Del {
+			lines = append(lines, e.NewLine)
+			changed = append(changed, e.Op == Ins)
+		}
+	}
+	return lines, changed
+}
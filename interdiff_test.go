@@ -0,0 +1,55 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestNewlyChanged(t *testing.T) {
+	base := []string{"a\n", "b\n", "c\n", "d\n", "e\n"}
+
+	tests := map[string]struct {
+		oldRev, newRev []string
+		want           []diff.LineRange
+	}{
+		"Unchanged": {
+			oldRev: []string{"a\n", "B\n", "c\n", "d\n", "e\n"},
+			newRev: []string{"a\n", "B\n", "c\n", "d\n", "e\n"},
+		},
+		"AdditionalChange": {
+			oldRev: []string{"a\n", "B\n", "c\n", "d\n", "e\n"},
+			newRev: []string{"a\n", "B\n", "c\n", "D\n", "E\n"},
+			want:   []diff.LineRange{{Start: 4, End: 5}},
+		},
+		"ChangeRevised": {
+			oldRev: []string{"a\n", "B\n", "c\n", "d\n", "e\n"},
+			newRev: []string{"a\n", "B2\n", "c\n", "d\n", "e\n"},
+			want:   []diff.LineRange{{Start: 2, End: 2}},
+		},
+		"ChangeReverted": {
+			oldRev: []string{"a\n", "B\n", "c\n", "d\n", "e\n"},
+			newRev: []string{"a\n", "b\n", "c\n", "d\n", "e\n"},
+		},
+		"ChangeMovedByInsertion": {
+			oldRev: []string{"a\n", "B\n", "c\n", "d\n", "e\n"},
+			newRev: []string{"new\n", "a\n", "B\n", "c\n", "d\n", "e\n"},
+			want:   []diff.LineRange{{Start: 1, End: 1}},
+		},
+		"SeparateRanges": {
+			oldRev: base,
+			newRev: []string{"A\n", "b\n", "C\n", "D\n", "e\n"},
+			want:   []diff.LineRange{{Start: 1, End: 1}, {Start: 3, End: 4}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.NewlyChanged(diff.Lines(base, test.oldRev), diff.Lines(base, test.newRev))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("NewlyChanged() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonEdit is the JSON form of an [Edit] written by [WriteJSONL].
+type jsonEdit struct {
+	Op  string  `json:"op"`
+	Old *string `json:"old,omitempty"`
+	New *string `json:"new,omitempty"`
+}
+
+// WriteJSONL writes the edits to w as newline-delimited JSON, one compact object per line such
+// as {"op":"del","old":"foo\n"}. The op is "eq", "del" or "ins". An object has an "old" field
+// for "eq" and "del" and a "new" field for "eq" and "ins". Equal lines are written as well so
+// consumers can keep track of line numbers.
+func WriteJSONL(w io.Writer, edits []Edit) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, e := range edits {
+		var je jsonEdit
+		switch e.Op {
+		case Eq:
+			je = jsonEdit{Op: "eq", Old: &e.OldLine, New: &e.NewLine}
+		case Del:
+			je = jsonEdit{Op: "del", Old: &e.OldLine}
+		case Ins:
+			je = jsonEdit{Op: "ins", New: &e.NewLine}
+		}
+		if err := enc.Encode(je); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonDocument is the JSON form of an edit script written by [WriteJSON].
+type jsonDocument struct {
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+	Edits      []Edit `json:"edits"`
+}
+
+// WriteJSON writes the edits to w as a single JSON object such as
+//
+//	{"insertions":1,"deletions":0,"edits":[{"op":"ins","oldLine":"","newLine":"foo\n"}]}
+//
+// holding the number of inserted and deleted lines and the edits in order. Unlike [WriteJSONL],
+// every edit has both an "oldLine" and a "newLine" field, so empty lines are written as is. Use
+// [ReadJSON] to read the edits back.
+func WriteJSON(w io.Writer, edits []Edit) error {
+	ins, del := countChanges(edits)
+	doc := jsonDocument{Insertions: ins, Deletions: del, Edits: edits}
+	if doc.Edits == nil {
+		doc.Edits = []Edit{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
+
+// ReadJSON reads the edits written by [WriteJSON] from r. The counts of inserted and deleted
+// lines are not needed to read the edits and are ignored.
+func ReadJSON(r io.Reader) ([]Edit, error) {
+	var doc jsonDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc.Edits, nil
+}
+
+// MarshalJSON encodes op as one of the strings "ins", "del" or "eq".
+func (op OpType) MarshalJSON() ([]byte, error) {
+	switch op {
+	case Ins:
+		return []byte(`"ins"`), nil
+	case Del:
+		return []byte(`"del"`), nil
+	case Eq:
+		return []byte(`"eq"`), nil
+	default:
+		return nil, fmt.Errorf("diff: unknown OpType %d", int(op))
+	}
+}
+
+// UnmarshalJSON decodes op from one of the strings "ins", "del" or "eq".
+func (op *OpType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "ins":
+		*op = Ins
+	case "del":
+		*op = Del
+	case "eq":
+		*op = Eq
+	default:
+		return fmt.Errorf("diff: unknown op %q", s)
+	}
+	return nil
+}
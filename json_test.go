@@ -0,0 +1,92 @@
+package diff_test
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Del, OldLine: "if a < b {\n"},
+		{Op: diff.Ins, NewLine: ""},
+		{Op: diff.Ins, NewLine: "c"},
+	}
+
+	var buf bytes.Buffer
+	if err := diff.WriteJSONL(&buf, edits); err != nil {
+		t.Fatalf("WriteJSONL() error: %v", err)
+	}
+
+	want := `{"op":"eq","old":"a\n","new":"a\n"}
+{"op":"del","old":"if a < b {\n"}
+{"op":"ins","new":""}
+{"op":"ins","new":"c"}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteJSONL() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Del, OldLine: "if a < b {\n"},
+		{Op: diff.Ins, NewLine: ""},
+		{Op: diff.Ins, NewLine: "c"},
+	}
+
+	var buf bytes.Buffer
+	if err := diff.WriteJSON(&buf, edits); err != nil {
+		t.Fatalf("WriteJSON() error: %v", err)
+	}
+
+	want := `{"insertions":2,"deletions":1,"edits":[` +
+		`{"op":"eq","oldLine":"a\n","newLine":"a\n"},` +
+		`{"op":"del","oldLine":"if a < b {\n","newLine":""},` +
+		`{"op":"ins","oldLine":"","newLine":""},` +
+		`{"op":"ins","oldLine":"","newLine":"c"}]}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteJSON() =\n%s\nwant:\n%s", got, want)
+	}
+
+	got, err := diff.ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSON() error: %v", err)
+	}
+	if !slices.Equal(got, edits) {
+		t.Errorf("ReadJSON() = %v, want %v", got, edits)
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := diff.WriteJSON(&buf, nil); err != nil {
+			t.Fatalf("WriteJSON() error: %v", err)
+		}
+		want := `{"insertions":0,"deletions":0,"edits":[]}` + "\n"
+		if got := buf.String(); got != want {
+			t.Errorf("WriteJSON() = %s, want %s", got, want)
+		}
+	})
+}
+
+func TestReadJSONInvalid(t *testing.T) {
+	tests := map[string]string{
+		"UnknownOp": `{"edits":[{"op":"mod","oldLine":"a","newLine":"b"}]}`,
+		"OpNumber":  `{"edits":[{"op":1,"oldLine":"a","newLine":""}]}`,
+		"Syntax":    `{"edits":[`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := diff.ReadJSON(strings.NewReader(input)); err == nil {
+				t.Errorf("ReadJSON(%s) expected error", input)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package diff
+
+import "time"
+
+// WithJunk keeps lines for which isJunk returns true, like blank lines or lone braces, from
+// anchoring the alignment of the sequences, like junk in Python's difflib. Lines common to both
+// sequences often match far from where they belong, splitting up runs of changes that are easier
+// to read as a whole. With junk, the edit script is first computed for the lines that are not
+// junk. Junk lines then only match where they extend the runs of matched lines or the start or
+// end of the sequences, all other junk lines are deleted or inserted. The edit script is thus not
+// necessarily the shortest. isJunk is called with the lines as compared, after any other option
+// transformed them.
+func WithJunk(isJunk func(string) bool) LinesOption {
+	return func(conf *linesConfig) {
+		conf.isJunk = isJunk
+	}
+}
+
+// junkOps computes the edit operations transforming oldKeys into newKeys where lines for which
+// isJunk returns true only match next to other matches as described by [WithJunk]. eq reports
+// whether oldKeys[x] equals newKeys[y]. A positive timeout bounds the search for the lines that
+// are not junk like [timedEditOps]; if it fires all of oldKeys is deleted and all of newKeys
+// inserted.
+func junkOps(oldKeys, newKeys []string, isJunk func(string) bool, eq func(x, y int) bool, timeout time.Duration, timedOut *bool) []OpType {
+	var oldIdx, newIdx []int // indexes of the lines that are not junk
+	for i, key := range oldKeys {
+		if !isJunk(key) {
+			oldIdx = append(oldIdx, i)
+		}
+	}
+	for i, key := range newKeys {
+		if !isJunk(key) {
+			newIdx = append(newIdx, i)
+		}
+	}
+
+	anchorEq := func(i, j int) bool {
+		return eq(oldIdx[i], newIdx[j])
+	}
+	var anchorOps []OpType
+	if timeout > 0 {
+		var expired bool
+		anchorOps = timedEditOps(len(oldIdx), len(newIdx), anchorEq, timeout, &expired)
+		if timedOut != nil {
+			*timedOut = expired
+		}
+		if expired {
+			return replaceOps(len(oldKeys), len(newKeys))
+		}
+	} else {
+		anchorOps = editOps(len(oldIdx), len(newIdx), anchorEq)
+	}
+
+	ops := make([]OpType, 0, len(oldKeys)+len(newKeys))
+	var x, y int // lines of oldKeys and newKeys covered by ops
+	// gap adds the operations up to the anchor at oldKeys[toX] and newKeys[toY]. As there are no
+	// anchors in between, only a common prefix and suffix of the gap can match.
+	gap := func(toX, toY int) {
+		var prefix, suffix int
+		for x+prefix < toX && y+prefix < toY && eq(x+prefix, y+prefix) {
+			prefix++
+		}
+		for toX-suffix > x+prefix && toY-suffix > y+prefix && eq(toX-suffix-1, toY-suffix-1) {
+			suffix++
+		}
+		for range prefix {
+			ops = append(ops, Eq)
+		}
+		for range toX - x - prefix - suffix {
+			ops = append(ops, Del)
+		}
+		for range toY - y - prefix - suffix {
+			ops = append(ops, Ins)
+		}
+		for range suffix {
+			ops = append(ops, Eq)
+		}
+		x, y = toX, toY
+	}
+
+	var i, j int // position in oldIdx and newIdx
+	for _, op := range anchorOps {
+		switch op {
+		case Eq:
+			gap(oldIdx[i], newIdx[j])
+			ops = append(ops, Eq)
+			x++
+			y++
+			i++
+			j++
+		case Del:
+			i++
+		case Ins:
+			j++
+		}
+	}
+	gap(len(oldKeys), len(newKeys))
+	return ops
+}
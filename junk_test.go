@@ -0,0 +1,75 @@
+package diff_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestLinesJunk(t *testing.T) {
+	blank := func(s string) bool { return strings.TrimSpace(s) == "" }
+
+	tests := map[string]struct {
+		old, new []string
+		opts     []diff.LinesOption
+		want     []diff.Edit
+	}{
+		"BlankLineAnchorsWithoutJunk": {
+			old: []string{"a\n", "\n", "b\n"},
+			new: []string{"x\n", "\n", "y\n"},
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "a\n"},
+				{Op: diff.Ins, NewLine: "x\n"},
+				{Op: diff.Eq, OldLine: "\n", NewLine: "\n"},
+				{Op: diff.Del, OldLine: "b\n"},
+				{Op: diff.Ins, NewLine: "y\n"},
+			},
+		},
+		"BlankLineDoesNotAnchorAsJunk": {
+			old:  []string{"a\n", "\n", "b\n"},
+			new:  []string{"x\n", "\n", "y\n"},
+			opts: []diff.LinesOption{diff.WithJunk(blank)},
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "a\n"},
+				{Op: diff.Del, OldLine: "\n"},
+				{Op: diff.Del, OldLine: "b\n"},
+				{Op: diff.Ins, NewLine: "x\n"},
+				{Op: diff.Ins, NewLine: "\n"},
+				{Op: diff.Ins, NewLine: "y\n"},
+			},
+		},
+		"JunkExtendsMatches": {
+			old:  []string{"a\n", "\n", "b\n", "\n", "c\n"},
+			new:  []string{"a\n", "\n", "x\n", "\n", "c\n"},
+			opts: []diff.LinesOption{diff.WithJunk(blank)},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Eq, OldLine: "\n", NewLine: "\n"},
+				{Op: diff.Del, OldLine: "b\n"},
+				{Op: diff.Ins, NewLine: "x\n"},
+				{Op: diff.Eq, OldLine: "\n", NewLine: "\n"},
+				{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+			},
+		},
+		"OnlyJunk": {
+			old:  []string{"\n", "\n"},
+			new:  []string{"\n"},
+			opts: []diff.LinesOption{diff.WithJunk(blank)},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "\n", NewLine: "\n"},
+				{Op: diff.Del, OldLine: "\n"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines(test.old, test.new, test.opts...)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Lines() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package diff
+
+// LinesLinear computes the same edit script as [Lines] in less memory, for large inputs where the
+// memory of [Lines] is a concern. [Lines] keeps the furthest reaching paths of every iteration of
+// the Myers algorithm to reconstruct the edit script, which takes O(D²) memory for D changes.
+// LinesLinear only keeps those of the current iteration and reconstructs the path of [Lines] by
+// divide and conquer: it searches again from the start of a range of iterations to its end,
+// noting the diagonal the path passes through halfway, and recurses on both halves. This takes
+// O(N+M+D log D) memory for N and M lines at the cost of comparing lines about log D times as
+// often.
+func LinesLinear(oldLines, newLines []string) []Edit {
+	n, m := len(oldLines), len(newLines)
+	if n+m == 0 {
+		return nil
+	}
+	l := linear{
+		n: n,
+		m: m,
+		eq: func(x, y int) bool {
+			return oldLines[x] == newLines[y]
+		},
+		v:   make([]int, 2*(n+m)+1),
+		via: make([]int, 2*(n+m)+1),
+	}
+	d := l.distance()
+	l.ops = make([]OpType, 0, (n+m+d)/2)
+	clear(l.v)
+	l.path(l.save(0, 0), 0, d, n-m, 1)
+	ops := compact(l.ops, oldLines, newLines)
+	return toEdits(ops, oldLines, newLines)
+}
+
+// linear reconstructs the path found by [shortestEdit] without keeping its trace.
+type linear struct {
+	n, m int
+	eq   func(x, y int) bool // reports whether element x of the first equals element y of the second
+	v    []int               // furthest x reached on diagonal k at index k+n+m like in shortestEdit
+	via  []int               // diagonal the path to v[i] passes through in the halfway iteration
+	rows [][]int             // V arrays saved by the calls of path at each depth of the recursion
+	ops  []OpType
+}
+
+// distance runs the Myers algorithm on l.v and returns the number of iterations D it takes to
+// reach the end.
+func (l *linear) distance() int {
+	maxD := l.n + l.m
+	for d := range maxD + 1 {
+		for k := max(-d, -l.m+(-l.m+d)&1); k <= min(d, l.n); k += 2 {
+			l.step(d, k)
+			if x := l.v[k+maxD]; x >= l.n && x-k >= l.m {
+				return d
+			}
+		}
+	}
+	return maxD
+}
+
+// path appends the operations of iterations lo through hi of the path [backtrack] follows, which
+// is on diagonal k after iteration hi. before holds the V array before iteration lo as saved by
+// save. depth is the depth of the recursion, whose calls all save into the same row.
+func (l *linear) path(before []int, lo, hi, k, depth int) {
+	maxD := l.n + l.m
+	copy(l.v[traceOffset(lo, maxD):], before)
+	if lo == hi {
+		prevK, x := l.step(lo, k)
+		if lo > 0 {
+			if prevK == k+1 {
+				l.ops = append(l.ops, Ins)
+			} else {
+				l.ops = append(l.ops, Del)
+			}
+		}
+		for range l.v[k+maxD] - x {
+			l.ops = append(l.ops, Eq)
+		}
+		return
+	}
+
+	mid := (lo + hi) / 2
+	var after []int // V array before iteration mid+1
+	for d := lo; d <= hi; d++ {
+		for k := max(-d, -l.m+(-l.m+d)&1); k <= min(d, l.n); k += 2 {
+			prevK, _ := l.step(d, k)
+			if d == mid {
+				l.via[k+maxD] = k
+			} else if d > mid {
+				l.via[k+maxD] = l.via[prevK+maxD]
+			}
+		}
+		if d == mid {
+			after = l.save(mid+1, depth)
+		}
+	}
+	midK := l.via[k+maxD]
+	l.path(before, lo, mid, midK, depth+1)
+	l.path(after, mid+1, hi, k, depth+1)
+}
+
+// step computes the furthest reaching path on diagonal k in iteration d like [shortestEdit]. It
+// returns the diagonal the path came from and the x its snake starts at.
+func (l *linear) step(d, k int) (prevK, start int) {
+	i := k + l.n + l.m
+	var x int
+	if k == -d || k == -l.m || (k != d && k != l.n && l.v[i-1] < l.v[i+1]) {
+		prevK, x = k+1, l.v[i+1] // down i.e. insert
+	} else {
+		prevK, x = k-1, l.v[i-1]+1 // right i.e. delete
+	}
+	start = x
+	y := x - k
+	for x < l.n && y < l.m && l.eq(x, y) { // advance on snake i.e. diagonal
+		x++
+		y++
+	}
+	l.v[i] = x
+	return prevK, start
+}
+
+// save copies the diagonals of l.v read by iteration d, the ones [shortestEdit] keeps in its trace,
+// into the row of the given depth and returns it. The row is only overwritten by the next call at
+// the same depth, after the path through the saved iteration has been appended.
+func (l *linear) save(d, depth int) []int {
+	maxD := l.n + l.m
+	for len(l.rows) <= depth {
+		l.rows = append(l.rows, nil)
+	}
+	l.rows[depth] = append(l.rows[depth][:0], l.v[traceOffset(d, maxD):min(maxD+d+2, len(l.v))]...)
+	return l.rows[depth]
+}
@@ -0,0 +1,96 @@
+package diff_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestLinesLinear(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+	}{
+		"Empty":         {},
+		"Equal":         {a: []string{"a", "b"}, b: []string{"a", "b"}},
+		"AllInserted":   {b: []string{"a", "b"}},
+		"AllDeleted":    {a: []string{"a", "b"}},
+		"NothingCommon": {a: []string{"a", "b"}, b: []string{"c", "d", "e"}},
+		"Changes": {
+			a: []string{"a", "b", "c", "d", "e", "f", "g"},
+			b: []string{"w", "a", "b", "x", "y", "z", "e", "g"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.LinesLinear(test.a, test.b)
+			want := diff.Lines(test.a, test.b)
+			if !slices.Equal(got, want) {
+				t.Errorf("LinesLinear() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLinesLinearRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	random := func() []string {
+		s := make([]string, r.IntN(60))
+		for i := range s {
+			s[i] = string(rune('a' + r.IntN(4)))
+		}
+		return s
+	}
+
+	for i := range 2000 {
+		a, b := random(), random()
+		if got, want := diff.LinesLinear(a, b), diff.Lines(a, b); !slices.Equal(got, want) {
+			t.Fatalf("%d: LinesLinear(%q, %q) = %v, want %v", i, a, b, got, want)
+		}
+	}
+}
+
+func FuzzLinesLinear(f *testing.F) {
+	f.Add("", "")
+	f.Add("abc", "abc")
+	f.Add("abcabba", "cbabac")
+	f.Add("abcdefg", "wabxyzeg")
+	f.Add("aaaa", "bbb")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		oldLines, newLines := strings.Split(a, ""), strings.Split(b, "")
+		if got, want := diff.LinesLinear(oldLines, newLines), diff.Lines(oldLines, newLines); !slices.Equal(got, want) {
+			t.Errorf("LinesLinear(%q, %q) = %v, want %v", oldLines, newLines, got, want)
+		}
+	})
+}
+
+// BenchmarkLinesLinear compares the memory of the trace based [diff.Lines] to [diff.LinesLinear]
+// on 50k lines with every 25th line changed.
+func BenchmarkLinesLinear(b *testing.B) {
+	oldLines := make([]string, 50_000)
+	newLines := make([]string, len(oldLines))
+	for i := range oldLines {
+		oldLines[i] = "line " + strconv.Itoa(i) + "\n"
+		newLines[i] = oldLines[i]
+		if i%25 == 0 {
+			newLines[i] = "changed " + strconv.Itoa(i) + "\n"
+		}
+	}
+
+	b.Run("Lines", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			diff.Lines(oldLines, newLines)
+		}
+	})
+	b.Run("LinesLinear", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			diff.LinesLinear(oldLines, newLines)
+		}
+	})
+}
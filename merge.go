@@ -0,0 +1,136 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ConflictRegion is a range of base lines changed differently by both sides of a [Merge].
+type ConflictRegion struct {
+	BaseStart, BaseEnd int      // 0-indexed half-open range of the affected base lines
+	Start              int      // index of the region in the merged lines, which keep the base lines
+	Base, A, B         []string // lines of the region in the base and as changed by either side
+}
+
+// Merge merges the edit scripts a and b, which both transform base, into the merged lines. A
+// change of one side is applied if the other side does not touch the base lines it affects. Two
+// changes overlap if their ranges of affected base lines intersect or if they start at the same
+// base line, so insertions at the same position overlap as well. Overlapping changes are grouped
+// into a region that is applied if both sides change it the same way and is reported as a
+// [ConflictRegion] otherwise. The merged lines keep the base lines of a conflict region for the
+// caller to replace by its resolution.
+//
+// It returns an error if the old lines of a or b do not match base.
+func Merge(base []string, a, b []Edit) (result []string, conflicts []ConflictRegion, err error) {
+	ca, err := changes(base, a, "a")
+	if err != nil {
+		return nil, nil, err
+	}
+	cb, err := changes(base, b, "b")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var i, j, pos int
+	for i < len(ca) || j < len(cb) {
+		var start int
+		switch {
+		case i == len(ca):
+			start = cb[j].start
+		case j == len(cb):
+			start = ca[i].start
+		default:
+			start = min(ca[i].start, cb[j].start)
+		}
+		end := start
+		fromA, fromB := i, j
+		for extended := true; extended; {
+			extended = false
+			if i < len(ca) && (ca[i].start == start || ca[i].start < end) {
+				end = max(end, ca[i].end)
+				i++
+				extended = true
+			}
+			if j < len(cb) && (cb[j].start == start || cb[j].start < end) {
+				end = max(end, cb[j].end)
+				j++
+				extended = true
+			}
+		}
+
+		result = append(result, base[pos:start]...)
+		linesA := applyChanges(base, start, end, ca[fromA:i])
+		linesB := applyChanges(base, start, end, cb[fromB:j])
+		switch {
+		case fromB == j:
+			result = append(result, linesA...)
+		case fromA == i, slices.Equal(linesA, linesB):
+			result = append(result, linesB...)
+		default:
+			conflicts = append(conflicts, ConflictRegion{
+				BaseStart: start,
+				BaseEnd:   end,
+				Start:     len(result),
+				Base:      base[start:end],
+				A:         linesA,
+				B:         linesB,
+			})
+			result = append(result, base[start:end]...)
+		}
+		pos = end
+	}
+	result = append(result, base[pos:]...)
+	return result, conflicts, nil
+}
+
+// change replaces the base lines in the 0-indexed half-open range [start, end) by lines.
+type change struct {
+	start, end int
+	lines      []string
+}
+
+// changes returns the changes edits make to base in order. It returns an error naming the edits
+// by side if their old lines do not match base.
+func changes(base []string, edits []Edit, side string) ([]change, error) {
+	var cs []change
+	var x int
+	open := false
+	for _, e := range edits {
+		if e.Op != Ins && (x >= len(base) || e.OldLine != base[x]) {
+			return nil, fmt.Errorf("diff: edits of %s do not match base at line %d", side, x+1)
+		}
+		if e.Op == Eq {
+			open = false
+			x++
+			continue
+		}
+		if !open {
+			cs = append(cs, change{start: x, end: x})
+			open = true
+		}
+		c := &cs[len(cs)-1]
+		if e.Op == Del {
+			x++
+			c.end = x
+		} else {
+			c.lines = append(c.lines, e.NewLine)
+		}
+	}
+	if x != len(base) {
+		return nil, fmt.Errorf("diff: edits of %s end at line %d of %d base lines", side, x, len(base))
+	}
+	return cs, nil
+}
+
+// applyChanges returns the base lines in the range [start, end) with the changes cs applied,
+// which must lie within the range.
+func applyChanges(base []string, start, end int, cs []change) []string {
+	var lines []string
+	pos := start
+	for _, c := range cs {
+		lines = append(lines, base[pos:c.start]...)
+		lines = append(lines, c.lines...)
+		pos = c.end
+	}
+	return append(lines, base[pos:end]...)
+}
@@ -0,0 +1,125 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestMerge(t *testing.T) {
+	base := []string{"a", "b", "c", "d", "e"}
+
+	tests := map[string]struct {
+		a, b      []string
+		want      []string
+		conflicts []diff.ConflictRegion
+	}{
+		"Unchanged": {
+			a:    base,
+			b:    base,
+			want: base,
+		},
+		"OneSide": {
+			a:    []string{"a", "x", "c", "d", "e"},
+			b:    base,
+			want: []string{"a", "x", "c", "d", "e"},
+		},
+		"DisjointChanges": {
+			a:    []string{"x", "b", "c", "d", "e"},
+			b:    []string{"a", "b", "c", "d", "y", "z"},
+			want: []string{"x", "b", "c", "d", "y", "z"},
+		},
+		"AdjacentChanges": {
+			a:    []string{"a", "x", "c", "d", "e"},
+			b:    []string{"a", "b", "y", "d", "e"},
+			want: []string{"a", "x", "y", "d", "e"},
+		},
+		"SameChange": {
+			a:    []string{"a", "c", "d", "x", "e"},
+			b:    []string{"a", "c", "d", "x", "e"},
+			want: []string{"a", "c", "d", "x", "e"},
+		},
+		"OverlappingChanges": {
+			a:    []string{"a", "x", "d", "e"},
+			b:    []string{"a", "b", "y", "z", "e"},
+			want: base,
+			conflicts: []diff.ConflictRegion{
+				{
+					BaseStart: 1,
+					BaseEnd:   4,
+					Start:     1,
+					Base:      []string{"b", "c", "d"},
+					A:         []string{"x", "d"},
+					B:         []string{"b", "y", "z"},
+				},
+			},
+		},
+		"InsertionsAtSamePosition": {
+			a:    []string{"a", "b", "x", "c", "d", "e"},
+			b:    []string{"a", "b", "y", "c", "d"},
+			want: []string{"a", "b", "c", "d"},
+			conflicts: []diff.ConflictRegion{
+				{
+					BaseStart: 2,
+					BaseEnd:   2,
+					Start:     2,
+					Base:      []string{},
+					A:         []string{"x"},
+					B:         []string{"y"},
+				},
+			},
+		},
+		"ConflictAfterCleanChange": {
+			a:    []string{"a", "b", "c", "x"},
+			b:    []string{"n", "a", "b", "c", "y"},
+			want: []string{"n", "a", "b", "c", "d", "e"},
+			conflicts: []diff.ConflictRegion{
+				{
+					BaseStart: 3,
+					BaseEnd:   5,
+					Start:     4,
+					Base:      []string{"d", "e"},
+					A:         []string{"x"},
+					B:         []string{"y"},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, conflicts, err := diff.Merge(base, diff.Lines(base, test.a), diff.Lines(base, test.b))
+			if err != nil {
+				t.Fatalf("Merge() error: %v", err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Merge() result = %q, want %q", got, test.want)
+			}
+			if len(conflicts) != len(test.conflicts) {
+				t.Fatalf("Merge() conflicts = %+v, want %+v", conflicts, test.conflicts)
+			}
+			for i, c := range conflicts {
+				want := test.conflicts[i]
+				if c.BaseStart != want.BaseStart || c.BaseEnd != want.BaseEnd || c.Start != want.Start ||
+					!slices.Equal(c.Base, want.Base) || !slices.Equal(c.A, want.A) || !slices.Equal(c.B, want.B) {
+					t.Errorf("Merge() conflicts[%d] = %+v, want %+v", i, c, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeMismatchedBase(t *testing.T) {
+	base := []string{"a", "b"}
+	edits := diff.Lines([]string{"a", "c"}, []string{"a"})
+
+	_, _, err := diff.Merge(base, edits, diff.Lines(base, base))
+	if err == nil {
+		t.Fatal("Merge() expected error for edits not matching base")
+	}
+	_, _, err = diff.Merge(base, diff.Lines(base, base), diff.Lines(base[:1], base[:1]))
+	if err == nil {
+		t.Fatal("Merge() expected error for edits not covering base")
+	}
+}
@@ -0,0 +1,61 @@
+package diff
+
+// Metrics returns the length of the longest common subsequence of a and b and their edit
+// distance, the number of deletions and insertions of the shortest edit script, computed in a
+// single run of the Myers algorithm without building the edit script. They are related by
+//
+//	len(a) + len(b) = 2*lcsLen + distance
+//
+// as every element of a and b is either part of the common subsequence or deleted or inserted.
+func Metrics(a, b []string) (lcsLen, distance int) {
+	distance = Distance(a, b)
+	return (len(a) + len(b) - distance) / 2, distance
+}
+
+// Distance returns the edit distance of a and b, the number of deletions and insertions of the
+// shortest edit script, for cheaply telling how different they are. Unlike [Lines], it does not
+// keep the furthest reaching paths of every iteration of the Myers algorithm to build the edit
+// script, so it only takes O(N+M) memory for N and M lines.
+func Distance(a, b []string) int {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxD == 0 {
+		return 0
+	}
+	v := make([]int, 2*maxD+1)
+	for d := range maxD + 1 {
+		for k := -d; k <= d; k += 2 {
+			i := k + maxD
+			var x int
+			if k == -d || (k != d && v[i-1] < v[i+1]) {
+				x = v[i+1] // down i.e. insert
+			} else {
+				x = v[i-1] + 1 // right i.e. delete
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] { // advance on snake i.e. diagonal
+				x++
+				y++
+			}
+			v[i] = x
+			if x >= n && y >= m {
+				return d
+			}
+		}
+	}
+	return maxD
+}
+
+// CommonAffixes returns the number of lines a and b have in common at their start and at their
+// end, like for checking that only the tail changed. The suffix does not overlap the prefix, so
+// prefixLen+suffixLen is at most the length of the shorter sequence.
+func CommonAffixes(a, b []string) (prefixLen, suffixLen int) {
+	n := min(len(a), len(b))
+	for prefixLen < n && a[prefixLen] == b[prefixLen] {
+		prefixLen++
+	}
+	for suffixLen < n-prefixLen && a[len(a)-1-suffixLen] == b[len(b)-1-suffixLen] {
+		suffixLen++
+	}
+	return prefixLen, suffixLen
+}
@@ -0,0 +1,153 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestMetrics(t *testing.T) {
+	tests := map[string]struct {
+		a, b         []string
+		lcsLen, dist int
+	}{
+		"BothEmpty": {
+			lcsLen: 0, dist: 0,
+		},
+		"Equal": {
+			a: []string{"a", "b", "c"}, b: []string{"a", "b", "c"},
+			lcsLen: 3, dist: 0,
+		},
+		"AllInserted": {
+			b:      []string{"a", "b"},
+			lcsLen: 0, dist: 2,
+		},
+		"AllDeleted": {
+			a:      []string{"a", "b"},
+			lcsLen: 0, dist: 2,
+		},
+		"Myers": {
+			a: []string{"A", "B", "C", "A", "B", "B", "A"}, b: []string{"C", "B", "A", "B", "A", "C"},
+			lcsLen: 4, dist: 5,
+		},
+		"CompletelyDifferent": {
+			a: []string{"a", "b", "c"}, b: []string{"x", "y"},
+			lcsLen: 0, dist: 5,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lcsLen, dist := diff.Metrics(test.a, test.b)
+			if lcsLen != test.lcsLen || dist != test.dist {
+				t.Errorf("Metrics() = (%d, %d), want (%d, %d)", lcsLen, dist, test.lcsLen, test.dist)
+			}
+			if got := len(test.a) + len(test.b); got != 2*lcsLen+dist {
+				t.Errorf("len(a)+len(b) = %d, want 2*lcsLen+distance = %d", got, 2*lcsLen+dist)
+			}
+
+			var eq, changes int
+			for _, e := range diff.Lines(test.a, test.b) {
+				if e.Op == diff.Eq {
+					eq++
+				} else {
+					changes++
+				}
+			}
+			if eq != lcsLen || changes != dist {
+				t.Errorf("Lines() has %d equal and %d changed lines, want (%d, %d)", eq, changes, lcsLen, dist)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want int
+	}{
+		"BothEmpty": {
+			want: 0,
+		},
+		"Equal": {
+			a: []string{"a", "b", "c"}, b: []string{"a", "b", "c"},
+			want: 0,
+		},
+		"AllInserted": {
+			b:    []string{"a", "b"},
+			want: 2,
+		},
+		"AllDeleted": {
+			a:    []string{"a", "b"},
+			want: 2,
+		},
+		"Myers": {
+			a: []string{"A", "B", "C", "A", "B", "B", "A"}, b: []string{"C", "B", "A", "B", "A", "C"},
+			want: 5,
+		},
+		"CompletelyDifferent": {
+			a: []string{"a", "b", "c"}, b: []string{"x", "y"},
+			want: 5,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := diff.Distance(test.a, test.b); got != test.want {
+				t.Errorf("Distance() = %d, want %d", got, test.want)
+			}
+
+			var changes int
+			for _, e := range diff.Lines(test.a, test.b) {
+				if e.Op != diff.Eq {
+					changes++
+				}
+			}
+			if changes != test.want {
+				t.Errorf("Lines() has %d changed lines, want %d", changes, test.want)
+			}
+		})
+	}
+}
+
+func TestCommonAffixes(t *testing.T) {
+	tests := map[string]struct {
+		a, b           []string
+		prefix, suffix int
+	}{
+		"BothEmpty": {},
+		"Equal": {
+			a: []string{"a", "b"}, b: []string{"a", "b"},
+			prefix: 2, suffix: 0,
+		},
+		"TailChanged": {
+			a: []string{"a", "b", "c"}, b: []string{"a", "b", "x", "y"},
+			prefix: 2, suffix: 0,
+		},
+		"HeadChanged": {
+			a: []string{"x", "b", "c"}, b: []string{"y", "z", "b", "c"},
+			prefix: 0, suffix: 2,
+		},
+		"MiddleChanged": {
+			a: []string{"a", "b", "c", "d"}, b: []string{"a", "x", "d"},
+			prefix: 1, suffix: 1,
+		},
+		"Appended": {
+			a: []string{"a", "a"}, b: []string{"a", "a", "a"},
+			prefix: 2, suffix: 0,
+		},
+		"NothingInCommon": {
+			a: []string{"a"}, b: []string{"b"},
+			prefix: 0, suffix: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prefix, suffix := diff.CommonAffixes(test.a, test.b)
+			if prefix != test.prefix || suffix != test.suffix {
+				t.Errorf("CommonAffixes() = (%d, %d), want (%d, %d)", prefix, suffix, test.prefix, test.suffix)
+			}
+		})
+	}
+}
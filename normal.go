@@ -0,0 +1,51 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteNormal writes the edits to w in the normal format of GNU diff without options. Each run of
+// changes starts with a command such as "5,7c4,5": the range of old lines, a (append), c (change)
+// or d (delete) and the range of new lines, where an empty range is given by the line preceding
+// it. The command is followed by the deleted lines marked by "<" and the inserted lines marked by
+// ">", separated by "---" for c.
+func WriteNormal(w io.Writer, edits []Edit) error {
+	hunks, _ := buildHunks(edits, 0, 0, false)
+	bw := bufio.NewWriter(w)
+	for _, h := range hunks {
+		cmd := "c"
+		switch {
+		case h.countOld == 0:
+			cmd = "a"
+		case h.countNew == 0:
+			cmd = "d"
+		}
+		if _, err := fmt.Fprintf(bw, "%s%s%s\n", lineRange(h.startOld, h.countOld), cmd, lineRange(h.startNew, h.countNew)); err != nil {
+			return err
+		}
+		for _, e := range edits[h.start:h.end] {
+			if e.Op != Del {
+				continue
+			}
+			if err := writeMarkedLine(bw, "< ", e.OldLine); err != nil {
+				return err
+			}
+		}
+		if cmd == "c" {
+			if _, err := bw.WriteString("---\n"); err != nil {
+				return err
+			}
+		}
+		for _, e := range edits[h.start:h.end] {
+			if e.Op != Ins {
+				continue
+			}
+			if err := writeMarkedLine(bw, "> ", e.NewLine); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,56 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWriteNormal(t *testing.T) {
+	// expected output as written by GNU diff
+	tests := map[string]struct {
+		old, new []string
+		want     string
+	}{
+		"Equal": {
+			old: []string{"a\n"},
+			new: []string{"a\n"},
+		},
+		"ChangeDeleteAppend": {
+			old:  []string{"a\n", "b\n", "c\n", "d\n", "e\n", "f\n"},
+			new:  []string{"a\n", "X\n", "c\n", "e\n", "f\n", "Y\n", "Z\n"},
+			want: "2c2\n< b\n---\n> X\n4d3\n< d\n6a6,7\n> Y\n> Z\n",
+		},
+		"ChangeRanges": {
+			old:  []string{"1\n", "2\n", "3\n", "4\n", "a\n", "b\n", "c\n", "8\n"},
+			new:  []string{"1\n", "2\n", "4\n", "x\n", "y\n", "8\n"},
+			want: "3d2\n< 3\n5,7c4,5\n< a\n< b\n< c\n---\n> x\n> y\n",
+		},
+		"AppendToEmpty": {
+			new:  []string{"a\n", "b\n", "c\n"},
+			want: "0a1,3\n> a\n> b\n> c\n",
+		},
+		"DeleteAll": {
+			old:  []string{"a\n", "b\n"},
+			want: "1,2d0\n< a\n< b\n",
+		},
+		"NoNewlineAtEnd": {
+			old:  []string{"a\n", "b"},
+			new:  []string{"a\n"},
+			want: "2d1\n< b\n\\ No newline at end of file\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := diff.WriteNormal(&sb, diff.Lines(test.old, test.new)); err != nil {
+				t.Fatalf("WriteNormal() error: %v", err)
+			}
+			if got := sb.String(); got != test.want {
+				t.Errorf("WriteNormal() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
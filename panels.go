@@ -0,0 +1,60 @@
+package diff
+
+// PanelKind is the kind of a [PanelLine].
+type PanelKind int
+
+const (
+	// PanelEqual is a line present in both panels.
+	PanelEqual PanelKind = iota
+	// PanelDeleted is a line only present in the before panel.
+	PanelDeleted
+	// PanelInserted is a line only present in the after panel.
+	PanelInserted
+	// PanelPlaceholder is an empty row aligning a panel with a deleted or inserted line in the
+	// other panel.
+	PanelPlaceholder
+)
+
+// PanelLine is a row of a panel of a split diff view.
+type PanelLine struct {
+	Kind   PanelKind
+	Number int    // 1-indexed line number in its sequence, 0 for placeholders
+	Line   string // empty for placeholders
+}
+
+// SplitPanels splits edits into a before and an after panel for a split diff view. Both panels
+// have the same number of rows so that row i of before lines up with row i of after. Equal lines
+// are in the same row of both panels. Within a run of changes, deleted and inserted lines are
+// paired up row by row and the shorter side is padded with placeholders.
+func SplitPanels(edits []Edit) (before, after []PanelLine) {
+	var lineOld, lineNew int
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Eq {
+			lineOld++
+			lineNew++
+			before = append(before, PanelLine{Kind: PanelEqual, Number: lineOld, Line: edits[i].OldLine})
+			after = append(after, PanelLine{Kind: PanelEqual, Number: lineNew, Line: edits[i].NewLine})
+			i++
+			continue
+		}
+		var dels, inss []PanelLine
+		for ; i < len(edits) && edits[i].Op != Eq; i++ {
+			if edits[i].Op == Del {
+				lineOld++
+				dels = append(dels, PanelLine{Kind: PanelDeleted, Number: lineOld, Line: edits[i].OldLine})
+			} else {
+				lineNew++
+				inss = append(inss, PanelLine{Kind: PanelInserted, Number: lineNew, Line: edits[i].NewLine})
+			}
+		}
+		before = append(before, dels...)
+		after = append(after, inss...)
+		for range len(inss) - len(dels) {
+			before = append(before, PanelLine{Kind: PanelPlaceholder})
+		}
+		for range len(dels) - len(inss) {
+			after = append(after, PanelLine{Kind: PanelPlaceholder})
+		}
+	}
+	return before, after
+}
@@ -0,0 +1,49 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestSplitPanels(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a", NewLine: "a"},
+		{Op: diff.Del, OldLine: "b"},
+		{Op: diff.Del, OldLine: "c"},
+		{Op: diff.Ins, NewLine: "x"},
+		{Op: diff.Eq, OldLine: "d", NewLine: "d"},
+		{Op: diff.Ins, NewLine: "y"},
+	}
+
+	before, after := diff.SplitPanels(edits)
+
+	wantBefore := []diff.PanelLine{
+		{Kind: diff.PanelEqual, Number: 1, Line: "a"},
+		{Kind: diff.PanelDeleted, Number: 2, Line: "b"},
+		{Kind: diff.PanelDeleted, Number: 3, Line: "c"},
+		{Kind: diff.PanelEqual, Number: 4, Line: "d"},
+		{Kind: diff.PanelPlaceholder},
+	}
+	wantAfter := []diff.PanelLine{
+		{Kind: diff.PanelEqual, Number: 1, Line: "a"},
+		{Kind: diff.PanelInserted, Number: 2, Line: "x"},
+		{Kind: diff.PanelPlaceholder},
+		{Kind: diff.PanelEqual, Number: 3, Line: "d"},
+		{Kind: diff.PanelInserted, Number: 4, Line: "y"},
+	}
+	if !slices.Equal(before, wantBefore) {
+		t.Errorf("SplitPanels() before =\n%v\nwant:\n%v", before, wantBefore)
+	}
+	if !slices.Equal(after, wantAfter) {
+		t.Errorf("SplitPanels() after =\n%v\nwant:\n%v", after, wantAfter)
+	}
+}
+
+func TestSplitPanelsEmpty(t *testing.T) {
+	before, after := diff.SplitPanels(nil)
+	if before != nil || after != nil {
+		t.Errorf("SplitPanels(nil) = %v, %v, want nil, nil", before, after)
+	}
+}
@@ -0,0 +1,69 @@
+package diff
+
+import "unicode/utf8"
+
+// Granularity is the unit of the elements [Refine] compares.
+type Granularity int
+
+const (
+	// LineGranularity compares lines including their trailing newline.
+	LineGranularity Granularity = iota
+	// WordGranularity compares runs of letters, digits and underscores, runs of white space and
+	// any other single character like [Words].
+	WordGranularity
+	// RuneGranularity compares single runes.
+	RuneGranularity
+)
+
+// Refine zooms into edit, returning the edits transforming its old content into its new content
+// at the given granularity. The old content is the OldLine and the new content the NewLine of
+// edit regardless of its Op. A deleted line is thus refined into the deletion of all its
+// elements and an inserted line into the insertion of all its elements. A deleted line and the
+// inserted line replacing it are refined together by passing an edit holding both, like
+//
+//	Refine(Edit{Op: Del, OldLine: del.OldLine, NewLine: ins.NewLine}, WordGranularity)
+//
+// Concatenating the OldLine of the Del and Eq edits gives the old content and the NewLine of
+// the Ins and Eq edits gives the new content, so the edits can be refined further. It panics if
+// granularity is unknown.
+func Refine(edit Edit, granularity Granularity) []Edit {
+	var split func(string) []string
+	switch granularity {
+	case LineGranularity:
+		split = splitLines
+	case WordGranularity:
+		split = splitTokens
+	case RuneGranularity:
+		split = splitRunes
+	default:
+		panic("diff: unknown granularity")
+	}
+	oldElems, newElems := split(edit.OldLine), split(edit.NewLine)
+	ops := editOps(len(oldElems), len(newElems), func(x, y int) bool {
+		return oldElems[x] == newElems[y]
+	})
+	ops = compact(ops, oldElems, newElems)
+	return toEdits(ops, oldElems, newElems)
+}
+
+// Runes computes the shortest edit script to transform the string a into b rune by rune, for
+// highlighting which characters of a short string like a commit subject or an identifier changed.
+// The OldLine and NewLine of each edit hold a single rune. Invalid UTF-8 is compared byte by
+// byte. Runes are not grouped into grapheme clusters, so a combining mark is an element of its
+// own apart from the rune it modifies. It is a shorthand for
+//
+//	Refine(Edit{Op: Del, OldLine: a, NewLine: b}, RuneGranularity)
+func Runes(a, b string) []Edit {
+	return Refine(Edit{Op: Del, OldLine: a, NewLine: b}, RuneGranularity)
+}
+
+// splitRunes splits s into its runes. Invalid UTF-8 is split into single bytes.
+func splitRunes(s string) []string {
+	runes := make([]string, 0, len(s))
+	for len(s) > 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		runes = append(runes, s[:size])
+		s = s[size:]
+	}
+	return runes
+}
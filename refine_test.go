@@ -0,0 +1,169 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestRefine(t *testing.T) {
+	tests := map[string]struct {
+		edit        diff.Edit
+		granularity diff.Granularity
+		want        []diff.Edit
+	}{
+		"Words": {
+			edit:        diff.Edit{Op: diff.Del, OldLine: "the quick fox\n", NewLine: "the slow fox\n"},
+			granularity: diff.WordGranularity,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "the", NewLine: "the"},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Del, OldLine: "quick"},
+				{Op: diff.Ins, NewLine: "slow"},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Eq, OldLine: "fox", NewLine: "fox"},
+				{Op: diff.Eq, OldLine: "\n", NewLine: "\n"},
+			},
+		},
+		"Runes": {
+			edit:        diff.Edit{Op: diff.Del, OldLine: "slow", NewLine: "slöw"},
+			granularity: diff.RuneGranularity,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "s", NewLine: "s"},
+				{Op: diff.Eq, OldLine: "l", NewLine: "l"},
+				{Op: diff.Del, OldLine: "o"},
+				{Op: diff.Ins, NewLine: "ö"},
+				{Op: diff.Eq, OldLine: "w", NewLine: "w"},
+			},
+		},
+		"Lines": {
+			edit:        diff.Edit{Op: diff.Eq, OldLine: "a\nb\n", NewLine: "a\nc\n"},
+			granularity: diff.LineGranularity,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Del, OldLine: "b\n"},
+				{Op: diff.Ins, NewLine: "c\n"},
+			},
+		},
+		"Deleted": {
+			edit:        diff.Edit{Op: diff.Del, OldLine: "a b"},
+			granularity: diff.WordGranularity,
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "a"},
+				{Op: diff.Del, OldLine: " "},
+				{Op: diff.Del, OldLine: "b"},
+			},
+		},
+		"Inserted": {
+			edit:        diff.Edit{Op: diff.Ins, NewLine: "ab"},
+			granularity: diff.RuneGranularity,
+			want: []diff.Edit{
+				{Op: diff.Ins, NewLine: "a"},
+				{Op: diff.Ins, NewLine: "b"},
+			},
+		},
+		"InvalidUTF8": {
+			edit:        diff.Edit{Op: diff.Del, OldLine: "a\xff", NewLine: "a\xfe"},
+			granularity: diff.RuneGranularity,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a", NewLine: "a"},
+				{Op: diff.Del, OldLine: "\xff"},
+				{Op: diff.Ins, NewLine: "\xfe"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Refine(test.edit, test.granularity)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Refine() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRefineZoomsIn(t *testing.T) {
+	pair := diff.Edit{Op: diff.Del, OldLine: "x := 1\ny := 2\n", NewLine: "x := 1\ny := 3\n"}
+
+	var runes []diff.Edit
+	for _, line := range diff.Refine(pair, diff.LineGranularity) {
+		if line.Op == diff.Eq {
+			continue
+		}
+		// zoom into the changed line by pairing it with its replacement
+		if line.Op == diff.Del {
+			pair = diff.Edit{Op: diff.Del, OldLine: line.OldLine}
+			continue
+		}
+		pair.NewLine = line.NewLine
+		for _, word := range diff.Refine(pair, diff.WordGranularity) {
+			if word.Op == diff.Eq {
+				continue
+			}
+			runes = append(runes, diff.Refine(word, diff.RuneGranularity)...)
+		}
+	}
+	want := []diff.Edit{{Op: diff.Del, OldLine: "2"}, {Op: diff.Ins, NewLine: "3"}}
+	if !slices.Equal(runes, want) {
+		t.Errorf("Refine() = %q, want %q", runes, want)
+	}
+}
+
+func TestRunes(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want []diff.Edit
+	}{
+		"BothEmpty": {},
+		"Equal": {
+			a: "ab", b: "ab",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a", NewLine: "a"},
+				{Op: diff.Eq, OldLine: "b", NewLine: "b"},
+			},
+		},
+		"ChangedCharacter": {
+			a: "fix typo", b: "fix tpyo",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "f", NewLine: "f"},
+				{Op: diff.Eq, OldLine: "i", NewLine: "i"},
+				{Op: diff.Eq, OldLine: "x", NewLine: "x"},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Eq, OldLine: "t", NewLine: "t"},
+				{Op: diff.Del, OldLine: "y"},
+				{Op: diff.Eq, OldLine: "p", NewLine: "p"},
+				{Op: diff.Ins, NewLine: "y"},
+				{Op: diff.Eq, OldLine: "o", NewLine: "o"},
+			},
+		},
+		"MultiByte": {
+			a: "naïve", b: "naive",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "n", NewLine: "n"},
+				{Op: diff.Eq, OldLine: "a", NewLine: "a"},
+				{Op: diff.Del, OldLine: "ï"},
+				{Op: diff.Ins, NewLine: "i"},
+				{Op: diff.Eq, OldLine: "v", NewLine: "v"},
+				{Op: diff.Eq, OldLine: "e", NewLine: "e"},
+			},
+		},
+		"CombiningMark": {
+			a: "e", b: "e\u0301",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "e", NewLine: "e"},
+				{Op: diff.Ins, NewLine: "\u0301"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Runes(test.a, test.b)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Runes() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
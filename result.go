@@ -0,0 +1,76 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+)
+
+// Result is the diff of two named sequences, which can be rendered in the unified, context,
+// normal and JSON formats and summarized without computing the edits again.
+type Result struct {
+	OldName, NewName string
+	Edits            []Edit
+}
+
+// Compare computes the edits transforming oldLines into newLines like [Lines] and returns them
+// together with the names of the sequences.
+func Compare(oldName, newName string, oldLines, newLines []string, opts ...LinesOption) Result {
+	return Result{OldName: oldName, NewName: newName, Edits: Lines(oldLines, newLines, opts...)}
+}
+
+// Unified renders the result as a unified patch with the given number of context lines like
+// [UnifiedPatch]. It is empty if the sequences are equal.
+func (r Result) Unified(context int) string {
+	return string(unifiedPatch(r.OldName, r.NewName, r.Edits, context))
+}
+
+// Context renders the result in context format with the given number of context lines like
+// [WriteContext], preceded by the "***" and "---" lines naming the sequences. It is empty if the
+// sequences are equal.
+func (r Result) Context(context int) string {
+	if !slices.ContainsFunc(r.Edits, func(e Edit) bool { return e.Op != Eq }) {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("*** " + r.OldName + "\n--- " + r.NewName + "\n")
+	_ = WriteContext(&sb, r.Edits, context) // writing to a strings.Builder cannot fail
+	return sb.String()
+}
+
+// Normal renders the result in the normal format of GNU diff like [WriteNormal].
+func (r Result) Normal() string {
+	var sb strings.Builder
+	_ = WriteNormal(&sb, r.Edits) // writing to a strings.Builder cannot fail
+	return sb.String()
+}
+
+// JSONL renders the edits as newline-delimited JSON like [WriteJSONL].
+func (r Result) JSONL() string {
+	var sb strings.Builder
+	_ = WriteJSONL(&sb, r.Edits) // writing to a strings.Builder cannot fail
+	return sb.String()
+}
+
+// JSON renders the edits as a single JSON object like [WriteJSON].
+func (r Result) JSON() string {
+	var sb strings.Builder
+	_ = WriteJSON(&sb, r.Edits) // writing to a strings.Builder cannot fail
+	return sb.String()
+}
+
+// Stat renders the result as a line of git's --stat output named after the new sequence like
+// [StatLine].
+func (r Result) Stat(width int) string {
+	return StatLine(r.NewName, r.Edits, width)
+}
+
+// Numstat renders the result as a line of git's --numstat output named after the new sequence
+// like [NumstatLine].
+func (r Result) Numstat() string {
+	return NumstatLine(r.NewName, r.Edits)
+}
+
+// Status classifies the change like [FileStatus].
+func (r Result) Status() Status {
+	return FileStatus(r.Edits)
+}
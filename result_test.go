@@ -0,0 +1,61 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestResult(t *testing.T) {
+	r := diff.Compare("a.txt", "b.txt", []string{"x\n", "y\n"}, []string{"x\n", "z\n"})
+
+	tests := map[string]struct {
+		got  string
+		want string
+	}{
+		"Unified": {
+			got:  r.Unified(1),
+			want: "--- a.txt\n+++ b.txt\n@@ -1,2 +1,2 @@\n x\n-y\n+z\n",
+		},
+		"Context": {
+			got:  r.Context(1),
+			want: "*** a.txt\n--- b.txt\n***************\n*** 1,2 ****\n  x\n! y\n--- 1,2 ----\n  x\n! z\n",
+		},
+		"ContextEqual": {
+			got:  diff.Compare("a.txt", "b.txt", []string{"x\n"}, []string{"x\n"}).Context(1),
+			want: "",
+		},
+		"Normal": {
+			got:  r.Normal(),
+			want: "2c2\n< y\n---\n> z\n",
+		},
+		"JSONL": {
+			got:  r.JSONL(),
+			want: "{\"op\":\"eq\",\"old\":\"x\\n\",\"new\":\"x\\n\"}\n{\"op\":\"del\",\"old\":\"y\\n\"}\n{\"op\":\"ins\",\"new\":\"z\\n\"}\n",
+		},
+		"JSON": {
+			got:  r.JSON(),
+			want: "{\"insertions\":1,\"deletions\":1,\"edits\":[{\"op\":\"eq\",\"oldLine\":\"x\\n\",\"newLine\":\"x\\n\"},{\"op\":\"del\",\"oldLine\":\"y\\n\",\"newLine\":\"\"},{\"op\":\"ins\",\"oldLine\":\"\",\"newLine\":\"z\\n\"}]}\n",
+		},
+		"Stat": {
+			got:  r.Stat(10),
+			want: "b.txt | 2 +-",
+		},
+		"Numstat": {
+			got:  r.Numstat(),
+			want: "1\t1\tb.txt",
+		},
+		"Status": {
+			got:  r.Status().String(),
+			want: "modified",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.got != test.want {
+				t.Errorf("%s() = %q, want %q", name, test.got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package diff
+
+// ReversePatch returns the edits transforming the new sequence of edits back into the old one,
+// like for rolling back a patch. Deletions become insertions and vice versa and equal lines swap
+// their old and new line. Within each run of changes, the deletions are ordered before the
+// insertions like in the edits computed by [Lines].
+func ReversePatch(edits []Edit) []Edit {
+	if edits == nil {
+		return nil
+	}
+	result := make([]Edit, 0, len(edits))
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Eq {
+			result = append(result, Edit{Op: Eq, OldLine: edits[i].NewLine, NewLine: edits[i].OldLine})
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].Op != Eq {
+			j++
+		}
+		for _, e := range edits[i:j] {
+			if e.Op == Ins {
+				result = append(result, Edit{Op: Del, OldLine: e.NewLine})
+			}
+		}
+		for _, e := range edits[i:j] {
+			if e.Op == Del {
+				result = append(result, Edit{Op: Ins, NewLine: e.OldLine})
+			}
+		}
+		i = j
+	}
+	return result
+}
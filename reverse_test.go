@@ -0,0 +1,75 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestReversePatch(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+	}{
+		"Empty":       {},
+		"Equal":       {a: []string{"a", "b"}, b: []string{"a", "b"}},
+		"AllInserted": {b: []string{"a", "b"}},
+		"AllDeleted":  {a: []string{"a", "b"}},
+		"Mixed": {
+			a: []string{"a", "b", "c", "d", "e", "f", "g", "h"},
+			b: []string{"a", "x", "y", "c", "d", "e", "f", "h", "i"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			forward := diff.Lines(test.a, test.b)
+			reverse := diff.ReversePatch(forward)
+
+			var old, new []string
+			for _, e := range reverse {
+				if e.Op != diff.Ins {
+					old = append(old, e.OldLine)
+				}
+				if e.Op != diff.Del {
+					new = append(new, e.NewLine)
+				}
+			}
+			if !slices.Equal(old, test.b) || !slices.Equal(new, test.a) {
+				t.Errorf("ReversePatch() transforms %q into %q, want %q into %q", old, new, test.b, test.a)
+			}
+
+			// applying forward then reverse yields the original
+			patched, err := diff.ApplyFuzzy(test.a, diff.Hunks(forward, 1), 0)
+			if err != nil {
+				t.Fatalf("ApplyFuzzy() forward error: %v", err)
+			}
+			if !slices.Equal(patched, test.b) {
+				t.Fatalf("ApplyFuzzy() forward = %q, want %q", patched, test.b)
+			}
+			restored, err := diff.ApplyFuzzy(patched, diff.Hunks(reverse, 1), 0)
+			if err != nil {
+				t.Fatalf("ApplyFuzzy() reverse error: %v", err)
+			}
+			if !slices.Equal(restored, test.a) {
+				t.Errorf("ApplyFuzzy() reverse = %q, want %q", restored, test.a)
+			}
+		})
+	}
+}
+
+func TestReversePatchOrdersDeletionsFirst(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Del, OldLine: "a"},
+		{Op: diff.Ins, NewLine: "x"},
+		{Op: diff.Eq, OldLine: "b", NewLine: "b"},
+	}
+	want := []diff.Edit{
+		{Op: diff.Del, OldLine: "x"},
+		{Op: diff.Ins, NewLine: "a"},
+		{Op: diff.Eq, OldLine: "b", NewLine: "b"},
+	}
+	if got := diff.ReversePatch(edits); !slices.Equal(got, want) {
+		t.Errorf("ReversePatch() = %v, want %v", got, want)
+	}
+}
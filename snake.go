@@ -0,0 +1,34 @@
+package diff
+
+// Snake is a maximal run of equal elements on the path through the edit graph.
+type Snake struct {
+	StartA, StartB int // 0-indexed start of the run in a and b
+	Length         int // number of equal elements
+}
+
+// Snakes returns the runs of equal lines of the shortest edit script transforming a into b as
+// found by the Myers algorithm, in order. Unlike [Lines], changes are not moved to their
+// canonical position, so the snakes are those of the algorithm's path.
+func Snakes(a, b []string) []Snake {
+	var snakes []Snake
+	var x, y int
+	prev := Del // operation preceding the current one
+	for _, op := range editOps(len(a), len(b), func(x, y int) bool { return a[x] == b[y] }) {
+		switch op {
+		case Eq:
+			if prev == Eq {
+				snakes[len(snakes)-1].Length++
+			} else {
+				snakes = append(snakes, Snake{StartA: x, StartB: y, Length: 1})
+			}
+			x++
+			y++
+		case Del:
+			x++
+		case Ins:
+			y++
+		}
+		prev = op
+	}
+	return snakes
+}
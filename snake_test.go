@@ -0,0 +1,43 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestSnakes(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want []diff.Snake
+	}{
+		"BothEmpty": {},
+		"Equal": {
+			a:    []string{"A", "B"},
+			b:    []string{"A", "B"},
+			want: []diff.Snake{{StartA: 0, StartB: 0, Length: 2}},
+		},
+		"CompletelyDifferent": {
+			a: []string{"A", "B"},
+			b: []string{"C", "D"},
+		},
+		"PaperExample": {
+			a: []string{"A", "B", "C", "A", "B", "B", "A"},
+			b: []string{"C", "B", "A", "B", "A", "C"},
+			want: []diff.Snake{
+				{StartA: 2, StartB: 0, Length: 1},
+				{StartA: 3, StartB: 2, Length: 2},
+				{StartA: 6, StartB: 4, Length: 1},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := diff.Snakes(test.a, test.b); !slices.Equal(got, test.want) {
+				t.Errorf("Snakes() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package diff
+
+// LinesSorted computes an edit script transforming oldLines into newLines in O(N+M) time by
+// merging them like a merge join. Lines only in oldLines are deleted, lines only in newLines are
+// inserted and lines in both are equal. Duplicate lines are matched one to one.
+//
+// Both oldLines and newLines must be sorted in increasing order as defined by the < operator on
+// strings; the edit script is not minimal otherwise. The edits follow the sorted order, with
+// deletions and insertions of different lines ordered by their line.
+func LinesSorted(oldLines, newLines []string) []Edit {
+	if len(oldLines)+len(newLines) == 0 {
+		return nil
+	}
+	edits := make([]Edit, 0, max(len(oldLines), len(newLines)))
+	var x, y int
+	for x < len(oldLines) && y < len(newLines) {
+		switch {
+		case oldLines[x] == newLines[y]:
+			edits = append(edits, Edit{Op: Eq, OldLine: oldLines[x], NewLine: newLines[y]})
+			x++
+			y++
+		case oldLines[x] < newLines[y]:
+			edits = append(edits, Edit{Op: Del, OldLine: oldLines[x]})
+			x++
+		default:
+			edits = append(edits, Edit{Op: Ins, NewLine: newLines[y]})
+			y++
+		}
+	}
+	for ; x < len(oldLines); x++ {
+		edits = append(edits, Edit{Op: Del, OldLine: oldLines[x]})
+	}
+	for ; y < len(newLines); y++ {
+		edits = append(edits, Edit{Op: Ins, NewLine: newLines[y]})
+	}
+	return edits
+}
@@ -0,0 +1,66 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestLinesSorted(t *testing.T) {
+	tests := map[string]struct {
+		oldLines []string
+		newLines []string
+		want     []diff.Edit
+	}{
+		"BothEmpty": {
+			want: nil,
+		},
+		"FirstEmpty": {
+			newLines: []string{"a", "b"},
+			want: []diff.Edit{
+				{Op: diff.Ins, NewLine: "a"},
+				{Op: diff.Ins, NewLine: "b"},
+			},
+		},
+		"SecondEmpty": {
+			oldLines: []string{"a", "b"},
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "a"},
+				{Op: diff.Del, OldLine: "b"},
+			},
+		},
+		"AddedAndRemoved": {
+			oldLines: []string{"apple", "banana", "cherry", "fig"},
+			newLines: []string{"apple", "cherry", "date", "fig", "grape"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "apple", NewLine: "apple"},
+				{Op: diff.Del, OldLine: "banana"},
+				{Op: diff.Eq, OldLine: "cherry", NewLine: "cherry"},
+				{Op: diff.Ins, NewLine: "date"},
+				{Op: diff.Eq, OldLine: "fig", NewLine: "fig"},
+				{Op: diff.Ins, NewLine: "grape"},
+			},
+		},
+		"Duplicates": {
+			oldLines: []string{"a", "a", "b"},
+			newLines: []string{"a", "b", "b"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a", NewLine: "a"},
+				{Op: diff.Del, OldLine: "a"},
+				{Op: diff.Eq, OldLine: "b", NewLine: "b"},
+				{Op: diff.Ins, NewLine: "b"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.LinesSorted(test.oldLines, test.newLines)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("LinesSorted(%v, %v):\ngot:  %v\nwant: %v",
+					test.oldLines, test.newLines, got, test.want)
+			}
+		})
+	}
+}
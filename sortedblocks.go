@@ -0,0 +1,52 @@
+package diff
+
+import (
+	"cmp"
+	"slices"
+)
+
+// WithSortedBlocks compares lines ignoring their order within blocks, like the imports of a Go
+// file or the requirements of a go.mod file. A block consists of the lines following a line for
+// which isStart returns true up to the next line for which isEnd returns true, or up to the last
+// line. The lines of each block are sorted on both sides before the edit script is computed, so
+// reordering lines within a block is no change while adding or removing lines is. Lines outside
+// of blocks, including the lines starting and ending a block, are compared as usual. The edits
+// hold the lines of blocks in sorted order. isStart and isEnd are called with the lines as
+// compared, after any other option transformed them.
+func WithSortedBlocks(isStart, isEnd func(string) bool) LinesOption {
+	return func(conf *linesConfig) {
+		conf.blockStart = isStart
+		conf.blockEnd = isEnd
+	}
+}
+
+// sortBlocks returns copies of keys and of the lines they were computed from with the lines of
+// each block sorted by their key as described by [WithSortedBlocks].
+func sortBlocks(keys, lines []string, isStart, isEnd func(string) bool) ([]string, []string) {
+	idx := make([]int, len(keys))
+	for i := range idx {
+		idx[i] = i
+	}
+	for i := 0; i < len(keys); i++ {
+		if !isStart(keys[i]) {
+			continue
+		}
+		start := i + 1
+		end := start
+		for end < len(keys) && !isEnd(keys[end]) {
+			end++
+		}
+		slices.SortStableFunc(idx[start:end], func(a, b int) int {
+			return cmp.Compare(keys[a], keys[b])
+		})
+		i = end
+	}
+
+	sortedKeys := make([]string, len(keys))
+	sortedLines := make([]string, len(lines))
+	for i, j := range idx {
+		sortedKeys[i] = keys[j]
+		sortedLines[i] = lines[j]
+	}
+	return sortedKeys, sortedLines
+}
@@ -0,0 +1,90 @@
+package diff_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWithSortedBlocks(t *testing.T) {
+	isStart := func(s string) bool { return strings.HasSuffix(s, "(\n") }
+	isEnd := func(s string) bool { return s == ")\n" }
+
+	tests := map[string]struct {
+		a, b []string
+		want []diff.Edit
+	}{
+		"ReorderedWithinBlock": {
+			a: []string{"import (\n", "\t\"os\"\n", "\t\"fmt\"\n", ")\n"},
+			b: []string{"import (\n", "\t\"fmt\"\n", "\t\"os\"\n", ")\n"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "import (\n", NewLine: "import (\n"},
+				{Op: diff.Eq, OldLine: "\t\"fmt\"\n", NewLine: "\t\"fmt\"\n"},
+				{Op: diff.Eq, OldLine: "\t\"os\"\n", NewLine: "\t\"os\"\n"},
+				{Op: diff.Eq, OldLine: ")\n", NewLine: ")\n"},
+			},
+		},
+		"AddedAndRemovedWithinBlock": {
+			a: []string{"import (\n", "\t\"os\"\n", "\t\"io\"\n", "\t\"fmt\"\n", ")\n"},
+			b: []string{"import (\n", "\t\"strings\"\n", "\t\"fmt\"\n", "\t\"os\"\n", ")\n"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "import (\n", NewLine: "import (\n"},
+				{Op: diff.Eq, OldLine: "\t\"fmt\"\n", NewLine: "\t\"fmt\"\n"},
+				{Op: diff.Del, OldLine: "\t\"io\"\n"},
+				{Op: diff.Eq, OldLine: "\t\"os\"\n", NewLine: "\t\"os\"\n"},
+				{Op: diff.Ins, NewLine: "\t\"strings\"\n"},
+				{Op: diff.Eq, OldLine: ")\n", NewLine: ")\n"},
+			},
+		},
+		"ReorderedOutsideBlock": {
+			a: []string{"b\n", "a\n", "import (\n", "\t\"os\"\n", ")\n"},
+			b: []string{"a\n", "b\n", "import (\n", "\t\"os\"\n", ")\n"},
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "b\n"},
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Ins, NewLine: "b\n"},
+				{Op: diff.Eq, OldLine: "import (\n", NewLine: "import (\n"},
+				{Op: diff.Eq, OldLine: "\t\"os\"\n", NewLine: "\t\"os\"\n"},
+				{Op: diff.Eq, OldLine: ")\n", NewLine: ")\n"},
+			},
+		},
+		"BlocksSortedIndependently": {
+			a: []string{"x (\n", "b\n", "a\n", ")\n", "y (\n", "d\n", "c\n", ")\n"},
+			b: []string{"x (\n", "a\n", "b\n", ")\n", "y (\n", "c\n", "d\n", ")\n"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "x (\n", NewLine: "x (\n"},
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+				{Op: diff.Eq, OldLine: ")\n", NewLine: ")\n"},
+				{Op: diff.Eq, OldLine: "y (\n", NewLine: "y (\n"},
+				{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+				{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+				{Op: diff.Eq, OldLine: ")\n", NewLine: ")\n"},
+			},
+		},
+		"UnterminatedBlock": {
+			a: []string{"x (\n", "b\n", "a\n"},
+			b: []string{"x (\n", "a\n", "b\n"},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "x (\n", NewLine: "x (\n"},
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, b := slices.Clone(test.a), slices.Clone(test.b)
+			got := diff.Lines(a, b, diff.WithSortedBlocks(isStart, isEnd))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Lines() = %v, want %v", got, test.want)
+			}
+			if !slices.Equal(a, test.a) || !slices.Equal(b, test.b) {
+				t.Errorf("Lines() modified its input")
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Stat returns the number of inserted and deleted lines of the edits.
+func Stat(edits []Edit) (insertions, deletions int) {
+	return countChanges(edits)
+}
+
+// WriteStat writes a summary of the size of the edits to w like the last line of git's --stat
+// output, such as " 3 lines changed, 2 insertions(+), 1 deletion(-)", for reporting the size of a
+// change without the diff. The changed lines are the inserted and deleted lines.
+func WriteStat(w io.Writer, edits []Edit) error {
+	ins, del := countChanges(edits)
+	_, err := fmt.Fprintf(w, " %s changed, %s(+), %s(-)\n", plural(ins+del, "line"), plural(ins, "insertion"), plural(del, "deletion"))
+	return err
+}
+
+// plural formats n followed by noun, adding an s unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// StatLine renders a single line of git's --stat output for the edits of the file name, such as
+// "name | 12 +++++-----". The bar of + and - has at most width characters; larger changes are
+// scaled down proportionally like git does, keeping at least one character for each non-zero
+// side, so a width of 1 still shows both sides of a change with insertions and deletions. It
+// panics if width is less than 1.
+func StatLine(name string, edits []Edit, width int) string {
+	if width < 1 {
+		panic("diff: stat width less than 1")
+	}
+	ins, del := countChanges(edits)
+	total := ins + del
+	if total == 0 {
+		return name + " | 0"
+	}
+	if total > width {
+		// scale the total first and derive the larger side from it, so the bar does not
+		// exceed width due to rounding up each side
+		scaled := max(scaleLinear(total, width, total), min(ins, 1)+min(del, 1))
+		if ins < del {
+			ins = scaleLinear(ins, width, total)
+			del = scaled - ins
+		} else {
+			del = scaleLinear(del, width, total)
+			ins = scaled - del
+		}
+	}
+	return fmt.Sprintf("%s | %d %s%s", name, total, strings.Repeat("+", ins), strings.Repeat("-", del))
+}
+
+// BinaryStatLine renders a single line of git's --stat output for the binary file name, such as
+// "name | Bin 0 -> 1234 bytes".
+func BinaryStatLine(name string, oldSize, newSize int64) string {
+	return fmt.Sprintf("%s | Bin %d -> %d bytes", name, oldSize, newSize)
+}
+
+// NumstatLine renders a single line of git's --numstat output for the edits of the file name,
+// such as "3\t1\tname": the number of inserted and deleted lines and the name, separated by tabs.
+// The name is quoted like git does by default if it contains special characters.
+func NumstatLine(name string, edits []Edit) string {
+	ins, del := countChanges(edits)
+	return fmt.Sprintf("%d\t%d\t%s", ins, del, quotePath(name))
+}
+
+// BinaryNumstatLine renders a single line of git's --numstat output for the binary file name,
+// which has "-" in place of the line counts.
+func BinaryNumstatLine(name string) string {
+	return "-\t-\t" + quotePath(name)
+}
+
+// quotePath quotes name like git does with core.quotePath enabled if it contains a double quote,
+// a backslash, a control character or a byte outside of ASCII. Within the quotes, these are
+// escaped like in C, using octal escapes for bytes without a short escape.
+func quotePath(name string) string {
+	needsQuote := false
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c == '"' || c == '\\' || c < 0x20 || c >= 0x7f {
+			needsQuote = true
+			break
+		}
+	}
+	if !needsQuote {
+		return name
+	}
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch c {
+		case '\a':
+			sb.WriteString(`\a`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\v':
+			sb.WriteString(`\v`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		default:
+			if c < 0x20 || c >= 0x7f {
+				fmt.Fprintf(&sb, "\\%03o", c)
+			} else {
+				sb.WriteByte(c)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// scaleLinear scales n from the range [0, total] to [0, width] the way git does for its stat
+// graph: any non-zero n is given at least one character.
+func scaleLinear(n, width, total int) int {
+	if n == 0 {
+		return 0
+	}
+	return 1 + n*(width-1)/total
+}
+
+// countChanges returns the number of inserted and deleted lines in edits.
+func countChanges(edits []Edit) (ins, del int) {
+	for _, e := range edits {
+		switch e.Op {
+		case Ins:
+			ins++
+		case Del:
+			del++
+		}
+	}
+	return ins, del
+}
@@ -0,0 +1,175 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestStat(t *testing.T) {
+	tests := map[string]struct {
+		a, b      []string
+		wantIns   int
+		wantDel   int
+		wantWrite string
+	}{
+		"Equal": {
+			a:         []string{"a"},
+			b:         []string{"a"},
+			wantWrite: " 0 lines changed, 0 insertions(+), 0 deletions(-)\n",
+		},
+		"One": {
+			a:         []string{"a", "b"},
+			b:         []string{"a"},
+			wantDel:   1,
+			wantWrite: " 1 line changed, 0 insertions(+), 1 deletion(-)\n",
+		},
+		"Changes": {
+			a:         []string{"a", "b", "c"},
+			b:         []string{"x", "b", "y", "z"},
+			wantIns:   3,
+			wantDel:   2,
+			wantWrite: " 5 lines changed, 3 insertions(+), 2 deletions(-)\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			edits := diff.Lines(test.a, test.b)
+			ins, del := diff.Stat(edits)
+			if ins != test.wantIns || del != test.wantDel {
+				t.Errorf("Stat() = %d, %d, want %d, %d", ins, del, test.wantIns, test.wantDel)
+			}
+
+			var sb strings.Builder
+			if err := diff.WriteStat(&sb, edits); err != nil {
+				t.Fatalf("WriteStat() error: %v", err)
+			}
+			if got := sb.String(); got != test.wantWrite {
+				t.Errorf("WriteStat() = %q, want %q", got, test.wantWrite)
+			}
+		})
+	}
+}
+
+func TestStatLine(t *testing.T) {
+	del := diff.Edit{Op: diff.Del, OldLine: "old\n"}
+	ins := diff.Edit{Op: diff.Ins, NewLine: "new\n"}
+	eq := diff.Edit{Op: diff.Eq, OldLine: "same\n", NewLine: "same\n"}
+
+	tests := map[string]struct {
+		edits []diff.Edit
+		width int
+		want  string
+	}{
+		"Unchanged": {
+			edits: []diff.Edit{eq},
+			width: 10,
+			want:  "a.txt | 0",
+		},
+		"OnlyInsertions": {
+			edits: []diff.Edit{eq, ins, ins, ins},
+			width: 10,
+			want:  "a.txt | 3 +++",
+		},
+		"OnlyDeletions": {
+			edits: []diff.Edit{del, del, eq},
+			width: 10,
+			want:  "a.txt | 2 --",
+		},
+		"Mixed": {
+			edits: []diff.Edit{del, del, ins, ins, ins, eq},
+			width: 10,
+			want:  "a.txt | 5 +++--",
+		},
+		"Scaled": {
+			// 12 insertions, 6 deletions scaled to 10: 1+6*9/18=4 deletions, 10-4=6 insertions
+			edits: append(repeat(ins, 12), repeat(del, 6)...),
+			width: 10,
+			want:  "a.txt | 18 ++++++----",
+		},
+		"ScaledEvenly": {
+			edits: append(repeat(ins, 7), repeat(del, 7)...),
+			width: 5,
+			want:  "a.txt | 14 ++---",
+		},
+		"ScaledKeepsSmallSide": {
+			edits: append(repeat(ins, 100), del),
+			width: 10,
+			want:  "a.txt | 101 +++++++++-",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.StatLine("a.txt", test.edits, test.width)
+			if got != test.want {
+				t.Errorf("StatLine() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBinaryStatLine(t *testing.T) {
+	want := "img.png | Bin 0 -> 1234 bytes"
+	got := diff.BinaryStatLine("img.png", 0, 1234)
+	if got != want {
+		t.Errorf("BinaryStatLine() = %q, want %q", got, want)
+	}
+}
+
+func repeat(e diff.Edit, n int) []diff.Edit {
+	edits := make([]diff.Edit, n)
+	for i := range edits {
+		edits[i] = e
+	}
+	return edits
+}
+
+func TestNumstatLine(t *testing.T) {
+	del := diff.Edit{Op: diff.Del, OldLine: "old\n"}
+	ins := diff.Edit{Op: diff.Ins, NewLine: "new\n"}
+	eq := diff.Edit{Op: diff.Eq, OldLine: "same\n", NewLine: "same\n"}
+
+	tests := map[string]struct {
+		name  string
+		edits []diff.Edit
+		want  string
+	}{
+		"Unchanged": {
+			name:  "a.txt",
+			edits: []diff.Edit{eq},
+			want:  "0\t0\ta.txt",
+		},
+		"Changed": {
+			name:  "dir/a.txt",
+			edits: []diff.Edit{eq, del, ins, ins},
+			want:  "2\t1\tdir/a.txt",
+		},
+		"QuotedSpecialCharacters": {
+			name:  "a\t\"b\"\\c",
+			edits: []diff.Edit{ins},
+			want:  `1	0	"a\t\"b\"\\c"`,
+		},
+		"QuotedNonASCII": {
+			name:  "café.txt",
+			edits: []diff.Edit{del},
+			want:  `0	1	"caf\303\251.txt"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := diff.NumstatLine(test.name, test.edits); got != test.want {
+				t.Errorf("NumstatLine() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBinaryNumstatLine(t *testing.T) {
+	if got, want := diff.BinaryNumstatLine("image.png"), "-\t-\timage.png"; got != want {
+		t.Errorf("BinaryNumstatLine() = %q, want %q", got, want)
+	}
+}
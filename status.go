@@ -0,0 +1,67 @@
+package diff
+
+// Status classifies the change of a file as a whole.
+type Status int
+
+const (
+	// Unchanged is a file whose lines are all equal.
+	Unchanged Status = iota
+	// Added is a file whose lines are all inserted, so the old file was empty.
+	Added
+	// Deleted is a file whose lines are all deleted, so the new file is empty.
+	Deleted
+	// Modified is a file with any other change.
+	Modified
+)
+
+func (s Status) String() string {
+	switch s {
+	case Unchanged:
+		return "unchanged"
+	case Added:
+		return "added"
+	case Deleted:
+		return "deleted"
+	case Modified:
+		return "modified"
+	default:
+		panic("unknown Status")
+	}
+}
+
+// FileStatus classifies the change of a file from its edits. Edits without any lines, like those
+// of two empty files, are Unchanged.
+func FileStatus(edits []Edit) Status {
+	ins, del := countChanges(edits)
+	switch {
+	case ins == 0 && del == 0:
+		return Unchanged
+	case ins == len(edits):
+		return Added
+	case del == len(edits):
+		return Deleted
+	default:
+		return Modified
+	}
+}
+
+// IsAppendOnly reports whether b only appends lines to a: a is a prefix of b, so the edits
+// computed by [Lines] keep every line of a and insert lines only after the last of them. Lines
+// inserted before or in between the lines of a are not appended, and neither is a modified line,
+// which is a deletion and an insertion.
+func IsAppendOnly(a, b []string) bool {
+	var inserted bool
+	for _, edit := range Lines(a, b) {
+		switch edit.Op {
+		case Del:
+			return false
+		case Ins:
+			inserted = true
+		case Eq:
+			if inserted {
+				return false
+			}
+		}
+	}
+	return true
+}
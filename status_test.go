@@ -0,0 +1,107 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestFileStatus(t *testing.T) {
+	tests := map[string]struct {
+		old, new []string
+		want     diff.Status
+	}{
+		"BothEmpty": {
+			want: diff.Unchanged,
+		},
+		"Equal": {
+			old:  []string{"a\n", "b\n"},
+			new:  []string{"a\n", "b\n"},
+			want: diff.Unchanged,
+		},
+		"Added": {
+			new:  []string{"a\n", "b\n"},
+			want: diff.Added,
+		},
+		"Deleted": {
+			old:  []string{"a\n", "b\n"},
+			want: diff.Deleted,
+		},
+		"Modified": {
+			old:  []string{"a\n", "b\n"},
+			new:  []string{"a\n", "c\n"},
+			want: diff.Modified,
+		},
+		"Replaced": {
+			old:  []string{"a\n"},
+			new:  []string{"b\n"},
+			want: diff.Modified,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := diff.FileStatus(diff.Lines(test.old, test.new)); got != test.want {
+				t.Errorf("FileStatus() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsAppendOnly(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want bool
+	}{
+		"BothEmpty": {
+			want: true,
+		},
+		"Equal": {
+			a:    []string{"a", "b"},
+			b:    []string{"a", "b"},
+			want: true,
+		},
+		"Appended": {
+			a:    []string{"a", "b"},
+			b:    []string{"a", "b", "c", "d"},
+			want: true,
+		},
+		"AppendedToEmpty": {
+			b:    []string{"a"},
+			want: true,
+		},
+		"Prepended": {
+			a:    []string{"a", "b"},
+			b:    []string{"x", "a", "b"},
+			want: false,
+		},
+		"InsertedInBetween": {
+			a:    []string{"a", "b"},
+			b:    []string{"a", "x", "b"},
+			want: false,
+		},
+		"Modified": {
+			a:    []string{"a", "b"},
+			b:    []string{"a", "B", "c"},
+			want: false,
+		},
+		"Deleted": {
+			a:    []string{"a", "b"},
+			b:    []string{"a"},
+			want: false,
+		},
+		"Reordered": {
+			a:    []string{"a", "b"},
+			b:    []string{"b", "a"},
+			want: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := diff.IsAppendOnly(test.a, test.b); got != test.want {
+				t.Errorf("IsAppendOnly(%q, %q) = %t, want %t", test.a, test.b, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package diff
+
+import "strings"
+
+// Suggestion is a change to a range of old lines in the form of a GitHub suggested change, which
+// replaces the lines a review comment is attached to.
+type Suggestion struct {
+	StartLine, EndLine int      // 1-indexed inclusive range of old lines the comment is attached to
+	Lines              []string // lines replacing the old lines, empty to delete them
+}
+
+// String renders the suggestion as a GitHub suggestion block. A missing trailing newline of the
+// last line is added so the closing fence is on its own line.
+func (s Suggestion) String() string {
+	var sb strings.Builder
+	sb.WriteString("```suggestion\n")
+	for _, line := range s.Lines {
+		sb.WriteString(line)
+	}
+	if len(s.Lines) > 0 && !strings.HasSuffix(s.Lines[len(s.Lines)-1], "\n") {
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+// Suggestions returns a suggestion for each run of changes in edits, as grouped by [Hunks]
+// without context. A run replacing old lines suggests the new lines in their place, so a pure
+// deletion suggests no lines. A suggestion needs at least one old line to attach to, so a pure
+// insertion is attached to the old line before it, or after it if it inserts before the first
+// line, and repeats that line in the suggested lines. Insertions into an empty sequence have no
+// line to attach to and are omitted.
+func Suggestions(edits []Edit) []Suggestion {
+	var old []string
+	for _, e := range edits {
+		if e.Op != Ins {
+			old = append(old, e.OldLine)
+		}
+	}
+
+	var suggestions []Suggestion
+	for _, h := range Hunks(edits, 0) {
+		var lines []string
+		for _, e := range h.Edits {
+			if e.Op == Ins {
+				lines = append(lines, e.NewLine)
+			}
+		}
+
+		s := Suggestion{StartLine: h.OldStart, EndLine: h.OldStart + h.OldCount - 1, Lines: lines}
+		if h.OldCount == 0 {
+			if len(old) == 0 {
+				continue
+			}
+			// OldStart is the number of old lines before the insertion.
+			if h.OldStart > 0 {
+				s.StartLine, s.EndLine = h.OldStart, h.OldStart
+				s.Lines = append([]string{old[h.OldStart-1]}, lines...)
+			} else {
+				s.StartLine, s.EndLine = 1, 1
+				s.Lines = append(lines, old[0])
+			}
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions
+}
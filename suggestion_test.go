@@ -0,0 +1,117 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestSuggestions(t *testing.T) {
+	tests := map[string]struct {
+		edits []diff.Edit
+		want  []diff.Suggestion
+	}{
+		"NoChanges": {
+			edits: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+			},
+		},
+		"Replacement": {
+			edits: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Del, OldLine: "b\n"},
+				{Op: diff.Del, OldLine: "c\n"},
+				{Op: diff.Ins, NewLine: "B\n"},
+				{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+			},
+			want: []diff.Suggestion{
+				{StartLine: 2, EndLine: 3, Lines: []string{"B\n"}},
+			},
+		},
+		"Deletion": {
+			edits: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Del, OldLine: "b\n"},
+			},
+			want: []diff.Suggestion{
+				{StartLine: 2, EndLine: 2},
+			},
+		},
+		"Insertion": {
+			edits: []diff.Edit{
+				{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+				{Op: diff.Ins, NewLine: "b\n"},
+				{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+			},
+			want: []diff.Suggestion{
+				{StartLine: 1, EndLine: 1, Lines: []string{"a\n", "b\n"}},
+			},
+		},
+		"InsertionAtStart": {
+			edits: []diff.Edit{
+				{Op: diff.Ins, NewLine: "a\n"},
+				{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+			},
+			want: []diff.Suggestion{
+				{StartLine: 1, EndLine: 1, Lines: []string{"a\n", "b\n"}},
+			},
+		},
+		"InsertionIntoEmpty": {
+			edits: []diff.Edit{
+				{Op: diff.Ins, NewLine: "a\n"},
+			},
+		},
+		"MultipleRuns": {
+			edits: []diff.Edit{
+				{Op: diff.Del, OldLine: "a\n"},
+				{Op: diff.Ins, NewLine: "A\n"},
+				{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+				{Op: diff.Del, OldLine: "c\n"},
+			},
+			want: []diff.Suggestion{
+				{StartLine: 1, EndLine: 1, Lines: []string{"A\n"}},
+				{StartLine: 3, EndLine: 3},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Suggestions(test.edits)
+			if !slices.EqualFunc(got, test.want, func(a, b diff.Suggestion) bool {
+				return a.StartLine == b.StartLine && a.EndLine == b.EndLine && slices.Equal(a.Lines, b.Lines)
+			}) {
+				t.Errorf("Suggestions() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSuggestionString(t *testing.T) {
+	tests := map[string]struct {
+		s    diff.Suggestion
+		want string
+	}{
+		"Lines": {
+			s:    diff.Suggestion{StartLine: 1, EndLine: 2, Lines: []string{"a\n", "b\n"}},
+			want: "```suggestion\na\nb\n```\n",
+		},
+		"NoNewlineAtEnd": {
+			s:    diff.Suggestion{StartLine: 1, EndLine: 1, Lines: []string{"a"}},
+			want: "```suggestion\na\n```\n",
+		},
+		"Deletion": {
+			s:    diff.Suggestion{StartLine: 1, EndLine: 1},
+			want: "```suggestion\n```\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.s.String(); got != test.want {
+				t.Errorf("String() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
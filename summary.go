@@ -0,0 +1,60 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeSummary summarizes one hunk of a diff.
+type ChangeSummary struct {
+	StartOld, CountOld int    // range of the hunk in the old sequence as in a unified hunk header
+	StartNew, CountNew int    // range of the hunk in the new sequence as in a unified hunk header
+	Insertions         int    // number of inserted lines
+	Deletions          int    // number of deleted lines
+	FirstOld           string // first deleted line, empty if there is none
+	FirstNew           string // first inserted line, empty if there is none
+}
+
+// String renders the summary on a single line such as `L12–15: "foo" → "bar" (+2 -1)` using the
+// range of the hunk in the old sequence. Trailing newlines of the lines are omitted.
+func (s ChangeSummary) String() string {
+	lines := fmt.Sprintf("L%d", s.StartOld)
+	if s.CountOld > 1 {
+		lines = fmt.Sprintf("L%d–%d", s.StartOld, s.StartOld+s.CountOld-1)
+	}
+	return fmt.Sprintf("%s: %q → %q (+%d -%d)", lines,
+		strings.TrimSuffix(s.FirstOld, "\n"), strings.TrimSuffix(s.FirstNew, "\n"),
+		s.Insertions, s.Deletions)
+}
+
+// Summaries returns a summary of each hunk of the edits as grouped by [Write] with the given
+// number of context lines. It panics if context is negative.
+func Summaries(edits []Edit, context int) []ChangeSummary {
+	if context < 0 {
+		panic("diff: negative context")
+	}
+	hunks, _ := buildHunks(edits, context, context, false)
+	if len(hunks) == 0 {
+		return nil
+	}
+	summaries := make([]ChangeSummary, 0, len(hunks))
+	for _, h := range hunks {
+		s := ChangeSummary{
+			StartOld: h.startOld,
+			CountOld: h.countOld,
+			StartNew: h.startNew,
+			CountNew: h.countNew,
+		}
+		s.Insertions, s.Deletions = countChanges(edits[h.start:h.end])
+		for _, e := range edits[h.start:h.end] {
+			if e.Op == Del && s.FirstOld == "" {
+				s.FirstOld = e.OldLine
+			}
+			if e.Op == Ins && s.FirstNew == "" {
+				s.FirstNew = e.NewLine
+			}
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries
+}
@@ -0,0 +1,49 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestSummaries(t *testing.T) {
+	edits := []diff.Edit{
+		{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"},
+		{Op: diff.Del, OldLine: "foo\n"},
+		{Op: diff.Ins, NewLine: "bar\n"},
+		{Op: diff.Ins, NewLine: "baz\n"},
+		{Op: diff.Eq, OldLine: "b\n", NewLine: "b\n"},
+		{Op: diff.Eq, OldLine: "c\n", NewLine: "c\n"},
+		{Op: diff.Eq, OldLine: "d\n", NewLine: "d\n"},
+		{Op: diff.Del, OldLine: "gone\n"},
+	}
+
+	got := diff.Summaries(edits, 1)
+
+	want := []diff.ChangeSummary{
+		{StartOld: 1, CountOld: 3, StartNew: 1, CountNew: 4, Insertions: 2, Deletions: 1, FirstOld: "foo\n", FirstNew: "bar\n"},
+		{StartOld: 5, CountOld: 2, StartNew: 6, CountNew: 1, Deletions: 1, FirstOld: "gone\n"},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Summaries() =\n%v\nwant:\n%v", got, want)
+	}
+
+	wantStrings := []string{
+		`L1–3: "foo" → "bar" (+2 -1)`,
+		`L5–6: "gone" → "" (+0 -1)`,
+	}
+	for i, s := range got {
+		if s.String() != wantStrings[i] {
+			t.Errorf("Summaries()[%d].String() = %q, want %q", i, s.String(), wantStrings[i])
+		}
+	}
+}
+
+func TestSummariesNoChanges(t *testing.T) {
+	edits := []diff.Edit{{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"}}
+
+	if got := diff.Summaries(edits, 3); got != nil {
+		t.Errorf("Summaries() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,54 @@
+package diff
+
+import "time"
+
+// WithTimeout bounds the time spent searching for the shortest edit script, for batch jobs that
+// need a hard latency bound. If the search takes longer than timeout, it gives up and the edits
+// delete all old lines and insert all new lines, which is correct but not minimal. If timedOut
+// is not nil, it is set to whether the timeout fired. The time spent on other work, like
+// normalizing lines, is not bounded. Combined with [WithJunk], it bounds the search for the lines
+// that are not junk. It panics if timeout is not positive.
+func WithTimeout(timeout time.Duration, timedOut *bool) LinesOption {
+	if timeout <= 0 {
+		panic("diff: timeout not positive")
+	}
+	return func(conf *linesConfig) {
+		conf.timeout = timeout
+		conf.timedOut = timedOut
+	}
+}
+
+// timedEditOps computes the operations transforming a sequence of length n into one of length m
+// like [editOps] unless this takes longer than timeout, in which case all of the first is deleted
+// and all of the second inserted. It reports whether the timeout fired in timedOut unless it is
+// nil.
+func timedEditOps(n, m int, eq func(x, y int) bool, timeout time.Duration, timedOut *bool) []OpType {
+	var trace [][]int
+	if n+m > 0 {
+		trace = shortestEdit(n, m, -m, n, time.Now().Add(timeout), eq, nil)
+	}
+	expired := n+m > 0 && trace == nil
+	if timedOut != nil {
+		*timedOut = expired
+	}
+	if !expired {
+		if n+m == 0 {
+			return nil
+		}
+		return backtrack(n, m, -m, n, trace)
+	}
+	return replaceOps(n, m)
+}
+
+// replaceOps returns the operations deleting all of a sequence of length n and inserting all of
+// one of length m.
+func replaceOps(n, m int) []OpType {
+	ops := make([]OpType, 0, n+m)
+	for range n {
+		ops = append(ops, Del)
+	}
+	for range m {
+		ops = append(ops, Ins)
+	}
+	return ops
+}
@@ -0,0 +1,97 @@
+package diff_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Run("NotExceeded", func(t *testing.T) {
+		a := []string{"a", "b", "c"}
+		b := []string{"a", "x", "c"}
+
+		timedOut := true
+		got := diff.Lines(a, b, diff.WithTimeout(time.Minute, &timedOut))
+		if timedOut {
+			t.Error("Lines() timed out")
+		}
+		if want := diff.Lines(a, b); !slices.Equal(got, want) {
+			t.Errorf("Lines() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Exceeded", func(t *testing.T) {
+		a := make([]string, 2000)
+		b := make([]string, 2000)
+		for i := range a {
+			a[i] = fmt.Sprintf("a%d", i)
+			b[i] = fmt.Sprintf("b%d", i)
+		}
+		b[0] = a[0]
+
+		var timedOut bool
+		got := diff.Lines(a, b, diff.WithTimeout(time.Nanosecond, &timedOut))
+		if !timedOut {
+			t.Fatal("Lines() did not time out")
+		}
+		var old, new []string
+		for _, e := range got {
+			if e.Op == diff.Eq {
+				t.Fatalf("Lines() = %v, want only deletions and insertions", e)
+			}
+			if e.Op == diff.Del {
+				old = append(old, e.OldLine)
+			} else {
+				new = append(new, e.NewLine)
+			}
+		}
+		if !slices.Equal(old, a) || !slices.Equal(new, b) {
+			t.Error("Lines() edits do not delete all old lines and insert all new lines")
+		}
+	})
+
+	t.Run("WithJunk", func(t *testing.T) {
+		a := make([]string, 2000)
+		b := make([]string, 2000)
+		for i := range a {
+			a[i] = fmt.Sprintf("a%d", i)
+			b[i] = fmt.Sprintf("b%d", i)
+		}
+		b[0] = a[0]
+		isJunk := func(s string) bool { return s == "" }
+
+		var timedOut bool
+		got := diff.Lines(a, b, diff.WithJunk(isJunk), diff.WithTimeout(time.Nanosecond, &timedOut))
+		if !timedOut {
+			t.Fatal("Lines() did not time out")
+		}
+		for _, e := range got {
+			if e.Op == diff.Eq {
+				t.Fatalf("Lines() = %v, want only deletions and insertions", e)
+			}
+		}
+
+		timedOut = true
+		got = diff.Lines(a[:3], b[:3], diff.WithJunk(isJunk), diff.WithTimeout(time.Minute, &timedOut))
+		if timedOut {
+			t.Error("Lines() timed out")
+		}
+		if want := diff.Lines(a[:3], b[:3], diff.WithJunk(isJunk)); !slices.Equal(got, want) {
+			t.Errorf("Lines() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		timedOut := true
+		if got := diff.Lines(nil, nil, diff.WithTimeout(time.Nanosecond, &timedOut)); len(got) != 0 {
+			t.Errorf("Lines() = %v, want no edits", got)
+		}
+		if timedOut {
+			t.Error("Lines() timed out")
+		}
+	})
+}
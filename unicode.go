@@ -0,0 +1,13 @@
+package diff
+
+import "golang.org/x/text/unicode/norm"
+
+// WithUnicodeNormalization compares lines after normalizing them to the Unicode normalization
+// form, so composed and decomposed encodings of the same text, like "é" as U+00E9 or as "e"
+// followed by U+0301, are equal. Use [norm.NFC] unless you need another form. The edits still
+// hold the original lines.
+func WithUnicodeNormalization(form norm.Form) LinesOption {
+	return func(conf *linesConfig) {
+		conf.normalize = append(conf.normalize, form.String)
+	}
+}
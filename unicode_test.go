@@ -0,0 +1,47 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestLinesUnicodeNormalization(t *testing.T) {
+	composed := "caf\u00e9\n"
+	decomposed := "cafe\u0301\n"
+
+	tests := map[string]struct {
+		opts []diff.LinesOption
+		want []diff.Edit
+	}{
+		"WithoutNormalization": {
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: composed},
+				{Op: diff.Ins, NewLine: decomposed},
+			},
+		},
+		"NFC": {
+			opts: []diff.LinesOption{diff.WithUnicodeNormalization(norm.NFC)},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: composed, NewLine: decomposed},
+			},
+		},
+		"NFD": {
+			opts: []diff.LinesOption{diff.WithUnicodeNormalization(norm.NFD)},
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: composed, NewLine: decomposed},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines([]string{composed}, []string{decomposed}, test.opts...)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Lines() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package diff
+
+// ViewBlock is a block of a diff view as returned by [CollapsedView]. It either shows edits or
+// stands for a run of collapsed equal lines.
+type ViewBlock struct {
+	OldStart  int    // 1-indexed line in the old sequence of the first line of the block
+	NewStart  int    // 1-indexed line in the new sequence of the first line of the block
+	Edits     []Edit // edits to show, nil if the block is collapsed
+	Collapsed int    // number of collapsed equal lines, 0 if the block is shown
+}
+
+// CollapsedView splits edits into blocks for an expandable diff view. Changes and up to context
+// equal lines around them are shown as in the hunks of [Write], while the runs of equal lines in
+// between, before the first and after the last hunk are collapsed. Together the blocks cover all
+// edits in order, so a collapsed block can be expanded into the equal lines it stands for. It
+// panics if context is negative.
+func CollapsedView(edits []Edit, context int) []ViewBlock {
+	if context < 0 {
+		panic("diff: negative context")
+	}
+	hunks, _ := buildHunks(edits, context, context, false)
+
+	var blocks []ViewBlock
+	lineOld, lineNew := 1, 1
+	var pos int // index into edits up to which blocks have been created
+	add := func(end int, collapse bool) {
+		if end <= pos {
+			return
+		}
+		b := ViewBlock{OldStart: lineOld, NewStart: lineNew}
+		if collapse {
+			b.Collapsed = end - pos
+		} else {
+			b.Edits = edits[pos:end]
+		}
+		blocks = append(blocks, b)
+		for _, e := range edits[pos:end] {
+			if e.Op != Ins {
+				lineOld++
+			}
+			if e.Op != Del {
+				lineNew++
+			}
+		}
+		pos = end
+	}
+	for _, h := range hunks {
+		add(h.start, true)
+		add(h.end, false)
+	}
+	add(len(edits), true)
+	return blocks
+}
@@ -0,0 +1,65 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestCollapsedView(t *testing.T) {
+	eq := func(s string) diff.Edit { return diff.Edit{Op: diff.Eq, OldLine: s, NewLine: s} }
+	edits := []diff.Edit{
+		eq("a"), eq("b"), eq("c"),
+		{Op: diff.Del, OldLine: "x"},
+		eq("d"), eq("e"), eq("f"), eq("g"),
+		{Op: diff.Ins, NewLine: "y"},
+		eq("h"),
+	}
+
+	tests := map[string]struct {
+		edits   []diff.Edit
+		context int
+		want    []diff.ViewBlock
+	}{
+		"Empty": {
+			edits:   nil,
+			context: 1,
+			want:    nil,
+		},
+		"OnlyEqual": {
+			edits:   []diff.Edit{eq("a"), eq("b")},
+			context: 1,
+			want:    []diff.ViewBlock{{OldStart: 1, NewStart: 1, Collapsed: 2}},
+		},
+		"CollapsesGaps": {
+			edits:   edits,
+			context: 1,
+			want: []diff.ViewBlock{
+				{OldStart: 1, NewStart: 1, Collapsed: 2},
+				{OldStart: 3, NewStart: 3, Edits: edits[2:5]},
+				{OldStart: 6, NewStart: 5, Collapsed: 2},
+				{OldStart: 8, NewStart: 7, Edits: edits[7:10]},
+			},
+		},
+		"MergedHunk": {
+			edits:   edits,
+			context: 3,
+			want: []diff.ViewBlock{
+				{OldStart: 1, NewStart: 1, Edits: edits},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.CollapsedView(test.edits, test.context)
+			if !slices.EqualFunc(got, test.want, func(a, b diff.ViewBlock) bool {
+				return a.OldStart == b.OldStart && a.NewStart == b.NewStart &&
+					a.Collapsed == b.Collapsed && slices.Equal(a.Edits, b.Edits)
+			}) {
+				t.Errorf("CollapsedView() =\n%v\nwant:\n%v", got, test.want)
+			}
+		})
+	}
+}
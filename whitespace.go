@@ -0,0 +1,194 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithIgnoreSpaceChange compares lines ignoring changes in the amount of white space exactly like
+// GNU diff -b. Runs of white space within a line are equal regardless of their length, so "a  b"
+// equals "a b", but white space is still a change where there was none, so "ab" does not equal
+// "a b". Trailing white space, including a missing final newline, is ignored. White space is the
+// ASCII space, tab, newline, vertical tab, form feed and carriage return. The edits still hold the
+// original lines.
+func WithIgnoreSpaceChange() LinesOption {
+	return func(conf *linesConfig) {
+		conf.normalize = append(conf.normalize, collapseSpace)
+	}
+}
+
+// WithIgnoreWhitespace compares lines ignoring leading and trailing white space and changes in
+// the amount of white space within a line, so reindented lines are equal. It is like
+// [WithIgnoreSpaceChange] but also ignores leading white space. Unlike GNU diff -w, white space is
+// still a change where there was none within a line, so "ab" does not equal "a b". White space
+// is the same as for [WithIgnoreSpaceChange]. The edits still hold the original lines, so an Eq
+// edit shows the white space difference between its OldLine and NewLine.
+func WithIgnoreWhitespace() LinesOption {
+	return func(conf *linesConfig) {
+		conf.normalize = append(conf.normalize, func(s string) string {
+			return collapseSpace(strings.TrimLeftFunc(s, isSpace))
+		})
+	}
+}
+
+// IsReindentOnly reports whether a and b are equal line for line when ignoring leading white
+// space, as when a change only reindents code. This is a cheap check as the lines are not
+// diffed. Equal sequences are reindented only as well.
+func IsReindentOnly(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimLeft(a[i], " \t") != strings.TrimLeft(b[i], " \t") {
+			return false
+		}
+	}
+	return true
+}
+
+// WithIgnoreTabExpansion compares lines ignoring changes due to tab expansion like GNU diff -E.
+// Tabs are expanded to spaces up to the next multiple of tabWidth columns before comparing, so a
+// tab-indented line equals a line indented with spaces to the same column. The edits still hold
+// the original lines. It panics if tabWidth is less than 1.
+func WithIgnoreTabExpansion(tabWidth int) LinesOption {
+	if tabWidth < 1 {
+		panic("diff: tab width less than 1")
+	}
+	return func(conf *linesConfig) {
+		conf.normalize = append(conf.normalize, func(s string) string {
+			return expandTabs(s, tabWidth)
+		})
+	}
+}
+
+// expandTabs replaces each tab in s by spaces up to the next multiple of tabWidth columns,
+// counting each rune as one column.
+func expandTabs(s string, tabWidth int) string {
+	if !strings.ContainsRune(s, '\t') {
+		return s
+	}
+	var sb strings.Builder
+	var col int
+	for _, r := range s {
+		switch r {
+		case '\t':
+			spaces := tabWidth - col%tabWidth
+			sb.WriteString(strings.Repeat(" ", spaces))
+			col += spaces
+		case '\n':
+			sb.WriteRune(r)
+			col = 0
+		default:
+			sb.WriteRune(r)
+			col++
+		}
+	}
+	return sb.String()
+}
+
+// collapseSpace normalizes s for comparing lines ignoring changes in the amount of white space
+// exactly like GNU diff -b: trailing white space, including the '\n', is removed and every other
+// run of white space is replaced by a single space. As in GNU diff, white space is the ASCII space,
+// '\t', '\n', '\v', '\f' and '\r', so a missing final newline is not a change and other Unicode
+// white space like a no-break space is not white space.
+func collapseSpace(s string) string {
+	content := strings.TrimRightFunc(s, isSpace)
+	var sb strings.Builder
+	sb.Grow(len(content))
+	inSpace := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if isSpace(rune(c)) {
+			inSpace = true
+			continue
+		}
+		if inSpace {
+			sb.WriteByte(' ')
+			inSpace = false
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// isSpace reports whether r is white space in the C locale.
+func isSpace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
+
+// whitespaceOnly reports whether the changes in edits only change the amount of white space. This
+// is the case if the n-th deleted line equals the n-th inserted line after [collapseSpace] for
+// all deleted and inserted lines.
+func whitespaceOnly(edits []Edit) bool {
+	var dels, inss []string
+	for _, e := range edits {
+		switch e.Op {
+		case Del:
+			dels = append(dels, e.OldLine)
+		case Ins:
+			inss = append(inss, e.NewLine)
+		}
+	}
+	if len(dels) != len(inss) {
+		return false
+	}
+	for i := range dels {
+		if collapseSpace(dels[i]) != collapseSpace(inss[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// WhitespaceIssue is an inserted line that only adds trailing white space to the line it replaces.
+type WhitespaceIssue struct {
+	Line    int    // 1-indexed line number in the new sequence
+	Content string // inserted line
+}
+
+// String renders the issue like git diff --check, such as "3: trailing whitespace.".
+func (i WhitespaceIssue) String() string {
+	return fmt.Sprintf("%d: trailing whitespace.", i.Line)
+}
+
+// CheckWhitespace returns the inserted lines that equal the deleted line they replace except for
+// added trailing white space. Within each run of changes, the n-th inserted line replaces the n-th
+// deleted line.
+func CheckWhitespace(edits []Edit) []WhitespaceIssue {
+	var issues []WhitespaceIssue
+	var lineNew int
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Eq {
+			lineNew++
+			i++
+			continue
+		}
+		var dels []string
+		var k int // deleted lines replaced so far
+		for ; i < len(edits) && edits[i].Op != Eq; i++ {
+			e := edits[i]
+			if e.Op == Del {
+				dels = append(dels, e.OldLine)
+				continue
+			}
+			lineNew++
+			if k < len(dels) && addsTrailingSpace(dels[k], e.NewLine) {
+				issues = append(issues, WhitespaceIssue{Line: lineNew, Content: e.NewLine})
+			}
+			k++
+		}
+	}
+	return issues
+}
+
+// addsTrailingSpace reports whether inserted equals deleted with trailing white space added.
+func addsTrailingSpace(deleted, inserted string) bool {
+	del, _ := strings.CutSuffix(deleted, "\n")
+	ins, _ := strings.CutSuffix(inserted, "\n")
+	added, ok := strings.CutPrefix(ins, del)
+	return ok && added != "" && strings.TrimRight(added, " \t") == ""
+}
@@ -0,0 +1,214 @@
+package diff_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestLinesIgnoreTabExpansion(t *testing.T) {
+	oldLines := []string{
+		"func main() {\n",
+		"\tfmt.Println()\n",
+		"ab\tc\n",
+		"}\n",
+	}
+	newLines := []string{
+		"func main() {\n",
+		"    fmt.Println()\n",
+		"ab  c\n",
+		"}\n",
+	}
+
+	tests := map[string]struct {
+		tabWidth int
+		want     []diff.Edit
+	}{
+		"TabWidth4": {
+			tabWidth: 4,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "func main() {\n", NewLine: "func main() {\n"},
+				{Op: diff.Eq, OldLine: "\tfmt.Println()\n", NewLine: "    fmt.Println()\n"},
+				{Op: diff.Eq, OldLine: "ab\tc\n", NewLine: "ab  c\n"},
+				{Op: diff.Eq, OldLine: "}\n", NewLine: "}\n"},
+			},
+		},
+		"TabWidth8": {
+			tabWidth: 8,
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "func main() {\n", NewLine: "func main() {\n"},
+				{Op: diff.Del, OldLine: "\tfmt.Println()\n"},
+				{Op: diff.Del, OldLine: "ab\tc\n"},
+				{Op: diff.Ins, NewLine: "    fmt.Println()\n"},
+				{Op: diff.Ins, NewLine: "ab  c\n"},
+				{Op: diff.Eq, OldLine: "}\n", NewLine: "}\n"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines(oldLines, newLines, diff.WithIgnoreTabExpansion(test.tabWidth))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("diff.Lines():\ngot:  %q\nwant: %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLinesIgnoreSpaceChange(t *testing.T) {
+	// The expectations match GNU diff -b.
+	tests := map[string]struct {
+		old, new string
+		equal    bool
+	}{
+		"RunOfSpaces":         {old: "a  b\n", new: "a b\n", equal: true},
+		"SpaceAdded":          {old: "ab\n", new: "a b\n", equal: false},
+		"LeadingSpaceAdded":   {old: "a\n", new: " a\n", equal: false},
+		"LeadingSpaceChanged": {old: "\ta\n", new: "    a\n", equal: true},
+		"TrailingSpace":       {old: "a \n", new: "a\n", equal: true},
+		"TabAndSpace":         {old: "a\tb\n", new: "a b\n", equal: true},
+		"VerticalTab":         {old: "a\vb\n", new: "a b\n", equal: true},
+		"CarriageReturn":      {old: "a\r\n", new: "a\n", equal: true},
+		"MissingFinalNewline": {old: "a\n", new: "a", equal: true},
+		"BlankLines":          {old: "\n", new: "  \n", equal: true},
+		"NoBreakSpaceIsNotWS": {old: "a\u00a0b\n", new: "a b\n", equal: false},
+		"DifferentCharacters": {old: "a b\n", new: "a c\n", equal: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines([]string{test.old}, []string{test.new}, diff.WithIgnoreSpaceChange())
+			equal := len(got) == 1 && got[0].Op == diff.Eq
+			if equal != test.equal {
+				t.Errorf("Lines(%q, %q) = %q, want equal %v", test.old, test.new, got, test.equal)
+			}
+		})
+	}
+}
+
+func TestLinesIgnoreWhitespace(t *testing.T) {
+	tests := map[string]struct {
+		old, new string
+		equal    bool
+	}{
+		"RunOfSpaces":         {old: "a  b\n", new: "a b\n", equal: true},
+		"SpaceAdded":          {old: "ab\n", new: "a b\n", equal: false},
+		"LeadingSpaceAdded":   {old: "a\n", new: " a\n", equal: true},
+		"Reindented":          {old: "\tif a {\n", new: "        if  a {\n", equal: true},
+		"TrailingSpace":       {old: "a \n", new: "a\n", equal: true},
+		"CarriageReturn":      {old: "a\r\n", new: "a\n", equal: true},
+		"MissingFinalNewline": {old: "a\n", new: "a", equal: true},
+		"BlankLines":          {old: "\n", new: "  \n", equal: true},
+		"DifferentCharacters": {old: " a b\n", new: "a c\n", equal: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Lines([]string{test.old}, []string{test.new}, diff.WithIgnoreWhitespace())
+			equal := len(got) == 1 && got[0].Op == diff.Eq
+			if equal != test.equal {
+				t.Errorf("Lines(%q, %q) = %q, want equal %v", test.old, test.new, got, test.equal)
+			}
+			if equal && (got[0].OldLine != test.old || got[0].NewLine != test.new) {
+				t.Errorf("Lines(%q, %q) = %q, want original lines", test.old, test.new, got)
+			}
+		})
+	}
+}
+
+func TestCheckWhitespace(t *testing.T) {
+	tests := map[string]struct {
+		old, new []string
+		want     []diff.WhitespaceIssue
+	}{
+		"NoChanges": {
+			old: []string{"a\n"},
+			new: []string{"a\n"},
+		},
+		"TrailingSpaceAdded": {
+			old:  []string{"a\n", "b\n", "c\n"},
+			new:  []string{"a\n", "b  \n", "c\t\n"},
+			want: []diff.WhitespaceIssue{{Line: 2, Content: "b  \n"}, {Line: 3, Content: "c\t\n"}},
+		},
+		"TrailingSpaceRemoved": {
+			old: []string{"a \n"},
+			new: []string{"a\n"},
+		},
+		"ContentChanged": {
+			old: []string{"a\n"},
+			new: []string{"b \n"},
+		},
+		"InsertedLine": {
+			old: []string{"a\n"},
+			new: []string{"a\n", "b \n"},
+		},
+		"AfterInsertion": {
+			old:  []string{"a\n", "b\n"},
+			new:  []string{"new\n", "a\n", "b \n"},
+			want: []diff.WhitespaceIssue{{Line: 3, Content: "b \n"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.CheckWhitespace(diff.Lines(test.old, test.new))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("CheckWhitespace() = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	issue := diff.WhitespaceIssue{Line: 3, Content: "b \n"}
+	if got, want := issue.String(), "3: trailing whitespace."; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestIsReindentOnly(t *testing.T) {
+	tests := map[string]struct {
+		a, b []string
+		want bool
+	}{
+		"BothEmpty": {want: true},
+		"Equal": {
+			a:    []string{"func f() {\n", "\treturn\n", "}\n"},
+			b:    []string{"func f() {\n", "\treturn\n", "}\n"},
+			want: true,
+		},
+		"Reindented": {
+			a:    []string{"if x {\n", "\tf()\n", "}\n"},
+			b:    []string{"  if x {\n", "        f()\n", "  }\n"},
+			want: true,
+		},
+		"TrailingSpaceChanged": {
+			a:    []string{"f()\n"},
+			b:    []string{"f() \n"},
+			want: false,
+		},
+		"InnerSpaceChanged": {
+			a:    []string{"x = 1\n"},
+			b:    []string{"x  = 1\n"},
+			want: false,
+		},
+		"LineAdded": {
+			a:    []string{"f()\n"},
+			b:    []string{"f()\n", "g()\n"},
+			want: false,
+		},
+		"TokenChanged": {
+			a:    []string{"\tf()\n"},
+			b:    []string{"    g()\n"},
+			want: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := diff.IsReindentOnly(test.a, test.b); got != test.want {
+				t.Errorf("IsReindentOnly() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package diff
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordsText computes the shortest edit script to transform the text a into b word by word,
+// ignoring where lines break. This suits prose that was reflowed, where line-based diffs change
+// every line although the words stayed the same.
+//
+// The texts are split into words like in [Words]. Each word is an element of the edits together
+// with the white space following it, so concatenating the OldLine of the Del and Eq edits gives a
+// and the NewLine of the Ins and Eq edits gives b. Words are compared without their white space,
+// so replacing a newline by a space or changing indentation is not a change. White space at the
+// start of a text is an element of its own.
+func WordsText(a, b string) []Edit {
+	oldWords, oldKeys := splitWords(a)
+	newWords, newKeys := splitWords(b)
+	ops := editOps(len(oldKeys), len(newKeys), func(x, y int) bool {
+		return oldKeys[x] == newKeys[y]
+	})
+	ops = compact(ops, oldKeys, newKeys)
+	return toEdits(ops, oldWords, newWords)
+}
+
+// splitWords splits s into the tokens of [splitTokens] that are not white space, each followed by
+// its trailing white space. The keys are the tokens without white space.
+func splitWords(s string) (words, keys []string) {
+	for _, token := range splitTokens(s) {
+		r, _ := utf8.DecodeRuneInString(token)
+		if unicode.IsSpace(r) && len(words) > 0 {
+			words[len(words)-1] += token
+			continue
+		}
+		words = append(words, token)
+		if unicode.IsSpace(r) {
+			keys = append(keys, "")
+		} else {
+			keys = append(keys, token)
+		}
+	}
+	return words, keys
+}
+
+// Words computes the shortest edit script to transform the string a into b word by word, for
+// showing which words of an edited line changed. The strings are split into words of letters,
+// digits and underscores, runs of white space and single other characters like punctuation, each
+// an element of its own. Concatenating the OldLine of the Del and Eq edits gives a and the NewLine
+// of the Ins and Eq edits gives b. Unlike in [WordsText], white space is compared as well, so
+// reflowing text changes the runs of white space but leaves the words around them equal. Use
+// [WriteWords] to highlight the changed words. It is a shorthand for
+//
+//	Refine(Edit{Op: Del, OldLine: a, NewLine: b}, WordGranularity)
+func Words(a, b string) []Edit {
+	return Refine(Edit{Op: Del, OldLine: a, NewLine: b}, WordGranularity)
+}
+
+// WriteWords writes the text of word-level edits like those of [Words] to w, enclosing deleted
+// words in [-...-] and inserted words in {+...+} like git diff --word-diff=plain. Runs of deleted
+// or inserted words are enclosed as a whole, deletions before insertions.
+func WriteWords(w io.Writer, edits []Edit) error {
+	bw := bufio.NewWriter(w)
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Eq {
+			if _, err := bw.WriteString(edits[i].NewLine); err != nil {
+				return err
+			}
+			i++
+			continue
+		}
+		var del, ins strings.Builder
+		for ; i < len(edits) && edits[i].Op != Eq; i++ {
+			if edits[i].Op == Del {
+				del.WriteString(edits[i].OldLine)
+			} else {
+				ins.WriteString(edits[i].NewLine)
+			}
+		}
+		if del.Len() > 0 {
+			if _, err := bw.WriteString("[-" + del.String() + "-]"); err != nil {
+				return err
+			}
+		}
+		if ins.Len() > 0 {
+			if _, err := bw.WriteString("{+" + ins.String() + "+}"); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// LineEdit is a line [Edit] together with the word-level edits within a changed line.
+type LineEdit struct {
+	Edit
+	// Words holds the edits transforming the deleted line of a pair of changed lines into the
+	// inserted line word by word. Both lines of a pair hold the same words. It is nil for equal
+	// and unpaired lines.
+	Words []Edit
+}
+
+// LinesWithWords computes the edits like [Lines] and also the word-level edits of changed lines,
+// so a renderer can highlight the changes within a line. Pairs of a deleted and an inserted line
+// are compared word by word, where a word is a run of letters, digits and underscores, a run of
+// white space or any other single character. With [WithFuzzyThreshold], a deleted line directly
+// followed by an inserted line is a pair. Otherwise, the deleted lines of each run of changes
+// are paired with its inserted lines in order.
+func LinesWithWords(oldLines, newLines []string, opts ...LinesOption) []LineEdit {
+	conf := &linesConfig{}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	edits := Lines(oldLines, newLines, opts...)
+	if len(edits) == 0 {
+		return nil
+	}
+	result := make([]LineEdit, len(edits))
+	for i, e := range edits {
+		result[i].Edit = e
+	}
+	pair := func(del, ins int) {
+		words := Words(edits[del].OldLine, edits[ins].NewLine)
+		result[del].Words, result[ins].Words = words, words
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Eq {
+			i++
+			continue
+		}
+		j := i
+		var dels, inss []int
+		for ; j < len(edits) && edits[j].Op != Eq; j++ {
+			if edits[j].Op == Del {
+				dels = append(dels, j)
+			} else {
+				inss = append(inss, j)
+			}
+		}
+		if conf.fuzzyThreshold > 0 {
+			for k := i; k+1 < j; k++ {
+				if edits[k].Op == Del && edits[k+1].Op == Ins {
+					pair(k, k+1)
+				}
+			}
+		} else {
+			for k := range min(len(dels), len(inss)) {
+				pair(dels[k], inss[k])
+			}
+		}
+		i = j
+	}
+	return result
+}
+
+// splitTokens splits s into runs of word characters, runs of white space and single other
+// characters.
+func splitTokens(s string) []string {
+	var tokens []string
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		end := size
+		var class func(rune) bool
+		switch {
+		case isWordChar(r):
+			class = isWordChar
+		case unicode.IsSpace(r):
+			class = unicode.IsSpace
+		}
+		if class != nil {
+			for end < len(s) {
+				r, size := utf8.DecodeRuneInString(s[end:])
+				if !class(r) {
+					break
+				}
+				end += size
+			}
+		}
+		tokens = append(tokens, s[:end])
+		s = s[end:]
+	}
+	return tokens
+}
+
+func isWordChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
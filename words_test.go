@@ -0,0 +1,232 @@
+package diff_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/diff"
+)
+
+func TestWordsText(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want []diff.Edit
+	}{
+		"BothEmpty": {},
+		"Reflowed": {
+			a: "the quick brown\nfox jumps\n",
+			b: "the quick\nbrown fox jumps\n",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "the ", NewLine: "the "},
+				{Op: diff.Eq, OldLine: "quick ", NewLine: "quick\n"},
+				{Op: diff.Eq, OldLine: "brown\n", NewLine: "brown "},
+				{Op: diff.Eq, OldLine: "fox ", NewLine: "fox "},
+				{Op: diff.Eq, OldLine: "jumps\n", NewLine: "jumps\n"},
+			},
+		},
+		"ChangedWordAcrossLines": {
+			a: "the quick\nbrown fox",
+			b: "the quick red\nfox",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "the ", NewLine: "the "},
+				{Op: diff.Eq, OldLine: "quick\n", NewLine: "quick "},
+				{Op: diff.Del, OldLine: "brown "},
+				{Op: diff.Ins, NewLine: "red\n"},
+				{Op: diff.Eq, OldLine: "fox", NewLine: "fox"},
+			},
+		},
+		"LeadingWhiteSpace": {
+			a: "  indented",
+			b: "indented",
+			want: []diff.Edit{
+				{Op: diff.Del, OldLine: "  "},
+				{Op: diff.Eq, OldLine: "indented", NewLine: "indented"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.WordsText(test.a, test.b)
+			if !slices.Equal(got, test.want) {
+				t.Fatalf("WordsText() = %q, want %q", got, test.want)
+			}
+
+			var a, b strings.Builder
+			for _, e := range got {
+				if e.Op != diff.Ins {
+					a.WriteString(e.OldLine)
+				}
+				if e.Op != diff.Del {
+					b.WriteString(e.NewLine)
+				}
+			}
+			if a.String() != test.a || b.String() != test.b {
+				t.Errorf("WordsText() does not reconstruct the texts: got %q and %q", a.String(), b.String())
+			}
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := map[string]struct {
+		a, b      string
+		want      []diff.Edit
+		wantWrite string
+	}{
+		"Equal": {
+			a:         "foo bar",
+			b:         "foo bar",
+			want:      []diff.Edit{{Op: diff.Eq, OldLine: "foo", NewLine: "foo"}, {Op: diff.Eq, OldLine: " ", NewLine: " "}, {Op: diff.Eq, OldLine: "bar", NewLine: "bar"}},
+			wantWrite: "foo bar",
+		},
+		"ChangedWord": {
+			a: "if a < b {\n",
+			b: "if a <= b {\n",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "if", NewLine: "if"},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Eq, OldLine: "a", NewLine: "a"},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Eq, OldLine: "<", NewLine: "<"},
+				{Op: diff.Ins, NewLine: "="},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Eq, OldLine: "b", NewLine: "b"},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Eq, OldLine: "{", NewLine: "{"},
+				{Op: diff.Eq, OldLine: "\n", NewLine: "\n"},
+			},
+			wantWrite: "if a <{+=+} b {\n",
+		},
+		"ChangedIdentifier": {
+			a: "x := foo(y)",
+			b: "x := bar_baz(y)",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "x", NewLine: "x"},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Eq, OldLine: ":", NewLine: ":"},
+				{Op: diff.Eq, OldLine: "=", NewLine: "="},
+				{Op: diff.Eq, OldLine: " ", NewLine: " "},
+				{Op: diff.Del, OldLine: "foo"},
+				{Op: diff.Ins, NewLine: "bar_baz"},
+				{Op: diff.Eq, OldLine: "(", NewLine: "("},
+				{Op: diff.Eq, OldLine: "y", NewLine: "y"},
+				{Op: diff.Eq, OldLine: ")", NewLine: ")"},
+			},
+			wantWrite: "x := [-foo-]{+bar_baz+}(y)",
+		},
+		"WhiteSpaceRuns": {
+			a: "foo  bar",
+			b: "foo\nbar baz",
+			want: []diff.Edit{
+				{Op: diff.Eq, OldLine: "foo", NewLine: "foo"},
+				{Op: diff.Del, OldLine: "  "},
+				{Op: diff.Ins, NewLine: "\n"},
+				{Op: diff.Eq, OldLine: "bar", NewLine: "bar"},
+				{Op: diff.Ins, NewLine: " "},
+				{Op: diff.Ins, NewLine: "baz"},
+			},
+			wantWrite: "foo[-  -]{+\n+}bar{+ baz+}",
+		},
+		"Empty": {
+			b:         " x",
+			want:      []diff.Edit{{Op: diff.Ins, NewLine: " "}, {Op: diff.Ins, NewLine: "x"}},
+			wantWrite: "{+ x+}",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Words(test.a, test.b)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Words() = %q, want %q", got, test.want)
+			}
+
+			var sb strings.Builder
+			if err := diff.WriteWords(&sb, got); err != nil {
+				t.Fatalf("WriteWords() error: %v", err)
+			}
+			if got := sb.String(); got != test.wantWrite {
+				t.Errorf("WriteWords() = %q, want %q", got, test.wantWrite)
+			}
+		})
+	}
+}
+
+func TestLinesWithWords(t *testing.T) {
+	tests := map[string]struct {
+		old, new []string
+		opts     []diff.LinesOption
+		want     []diff.LineEdit
+	}{
+		"NoChanges": {
+			old: []string{"a\n"},
+			new: []string{"a\n"},
+			want: []diff.LineEdit{
+				{Edit: diff.Edit{Op: diff.Eq, OldLine: "a\n", NewLine: "a\n"}},
+			},
+		},
+		"ChangedLine": {
+			old: []string{"keep\n", "x := foo(1)\n"},
+			new: []string{"keep\n", "x := bar(1)\n"},
+			want: []diff.LineEdit{
+				{Edit: diff.Edit{Op: diff.Eq, OldLine: "keep\n", NewLine: "keep\n"}},
+				{Edit: diff.Edit{Op: diff.Del, OldLine: "x := foo(1)\n"}, Words: xFooToBar},
+				{Edit: diff.Edit{Op: diff.Ins, NewLine: "x := bar(1)\n"}, Words: xFooToBar},
+			},
+		},
+		"UnpairedInsertion": {
+			old: []string{"a b\n"},
+			new: []string{"a c\n", "new\n"},
+			want: []diff.LineEdit{
+				{Edit: diff.Edit{Op: diff.Del, OldLine: "a b\n"}, Words: aBToC},
+				{Edit: diff.Edit{Op: diff.Ins, NewLine: "a c\n"}, Words: aBToC},
+				{Edit: diff.Edit{Op: diff.Ins, NewLine: "new\n"}},
+			},
+		},
+		"FuzzyPairs": {
+			old:  []string{"unrelated\n", "a b\n"},
+			new:  []string{"a c\n"},
+			opts: []diff.LinesOption{diff.WithFuzzyThreshold(0.5)},
+			want: []diff.LineEdit{
+				{Edit: diff.Edit{Op: diff.Del, OldLine: "unrelated\n"}},
+				{Edit: diff.Edit{Op: diff.Del, OldLine: "a b\n"}, Words: aBToC},
+				{Edit: diff.Edit{Op: diff.Ins, NewLine: "a c\n"}, Words: aBToC},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.LinesWithWords(test.old, test.new, test.opts...)
+			if !slices.EqualFunc(got, test.want, func(a, b diff.LineEdit) bool {
+				return a.Edit == b.Edit && slices.Equal(a.Words, b.Words)
+			}) {
+				t.Errorf("LinesWithWords() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+var xFooToBar = []diff.Edit{
+	{Op: diff.Eq, OldLine: "x", NewLine: "x"},
+	{Op: diff.Eq, OldLine: " ", NewLine: " "},
+	{Op: diff.Eq, OldLine: ":", NewLine: ":"},
+	{Op: diff.Eq, OldLine: "=", NewLine: "="},
+	{Op: diff.Eq, OldLine: " ", NewLine: " "},
+	{Op: diff.Del, OldLine: "foo"},
+	{Op: diff.Ins, NewLine: "bar"},
+	{Op: diff.Eq, OldLine: "(", NewLine: "("},
+	{Op: diff.Eq, OldLine: "1", NewLine: "1"},
+	{Op: diff.Eq, OldLine: ")", NewLine: ")"},
+	{Op: diff.Eq, OldLine: "\n", NewLine: "\n"},
+}
+
+var aBToC = []diff.Edit{
+	{Op: diff.Eq, OldLine: "a", NewLine: "a"},
+	{Op: diff.Eq, OldLine: " ", NewLine: " "},
+	{Op: diff.Del, OldLine: "b"},
+	{Op: diff.Ins, NewLine: "c"},
+	{Op: diff.Eq, OldLine: "\n", NewLine: "\n"},
+}